@@ -0,0 +1,471 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"mttohmd/converter"
+	"mttohmd/entry"
+	"mttohmd/generator"
+	"mttohmd/merger"
+)
+
+// usageText -h で表示する使い方の説明（コマンドの一覧はこの後に続く）
+const usageText = `使い方: mttohmd <コマンド> [オプション] [ファイル...]
+
+はてなブログのMT (MovableType) 形式のエクスポートを、エントリーごとの
+MT形式のファイルとはてなブログのMarkdown形式のファイルに変換します。
+入力にはgzip・zipで圧縮したファイルや標準入力 (-) も指定でき、
+複数指定した場合は重複したエントリーを除いてまとめます。
+
+例:
+  mttohmd convert blog.export.txt
+  mttohmd convert -limit 10 -md-only -mds-dir out blog.export.txt
+  mttohmd list blog.export.txt
+  mttohmd show -index 3 blog.export.txt
+  mttohmd join -output blog.export.txt
+  mttohmd split -max-size 5MB blog.export.txt
+
+コマンド:
+`
+
+// command サブコマンドの定義
+type command struct {
+	name    string
+	args    string // 使い方に表示する引数の説明
+	summary string
+	// setup コマンドで使うオプションを登録する
+	setup func(fs *flag.FlagSet, v *flagValues)
+	// check オプションを解析した後にコマンド固有の指定を検証する（省略可）
+	check func(cfg *config) error
+	run   func(cfg config) error
+}
+
+// commands 使えるサブコマンドの一覧（使い方にはこの順に表示する）
+var commands = []command{
+	{
+		name:    "convert",
+		args:    "エクスポートファイル...",
+		summary: "エクスポートをエントリーごとのMT形式・Markdown形式のファイルに変換する",
+		setup: func(fs *flag.FlagSet, v *flagValues) {
+			v.addInputFlags(fs)
+			v.addOutputDirFlags(fs)
+			v.addRangeFlags(fs)
+			v.addFilenameFlags(fs)
+			v.addMarkdownFlags(fs)
+		},
+		check: requireInputs,
+		run:   runConvert,
+	},
+	{
+		name:    "list",
+		args:    "エクスポートファイル...",
+		summary: "エントリーの一覧（日付・状態・カテゴリー・タイトル）を表示する",
+		setup: func(fs *flag.FlagSet, v *flagValues) {
+			v.addInputFlags(fs)
+			v.addRangeFlags(fs)
+		},
+		check: requireInputs,
+		run:   runList,
+	},
+	{
+		name:    "show",
+		args:    "エクスポートファイル...",
+		summary: "1件のエントリーをMarkdownに変換して標準出力に表示する",
+		setup: func(fs *flag.FlagSet, v *flagValues) {
+			v.addInputFlags(fs)
+			v.addMarkdownFlags(fs)
+			fs.IntVar(&v.index, "index", v.index, "表示するエントリーの番号 (listで表示される番号)")
+			fs.StringVar(&v.basename, "basename", v.basename, "表示するエントリーのBASENAME")
+		},
+		check: checkShow,
+		run:   runShow,
+	},
+	{
+		name:    "stats",
+		args:    "エクスポートファイル...",
+		summary: "エクスポートの件数・期間・カテゴリーなどを集計して表示する",
+		setup: func(fs *flag.FlagSet, v *flagValues) {
+			v.addInputFlags(fs)
+		},
+		check: requireInputs,
+		run:   runStats,
+	},
+	{
+		name:    "validate",
+		args:    "エクスポートファイル...",
+		summary: "ファイルを書き出さずに解析と変換の問題を確認する",
+		setup: func(fs *flag.FlagSet, v *flagValues) {
+			v.addInputFlags(fs)
+			v.addFilenameFlags(fs)
+		},
+		check: requireInputs,
+		run:   runValidate,
+	},
+	{
+		name:    "reverse",
+		args:    "[Markdownファイル・ディレクトリ...]",
+		summary: "Markdownファイルを1つのMT形式のエクスポートにまとめる",
+		setup: func(fs *flag.FlagSet, v *flagValues) {
+			fs.StringVar(&v.output, "output", defaultReverseOutput, "出力するMT形式のファイル")
+			fs.StringVar(&v.mdsDir, "mds-dir", v.mdsDir, "入力を省略した場合に読み込むディレクトリ")
+		},
+		check: func(cfg *config) error {
+			if len(cfg.inputs) == 0 {
+				cfg.inputs = []string{cfg.mdsDir}
+			}
+			return nil
+		},
+		run: func(cfg config) error {
+			return runReverse(cfg.inputs, cfg.output)
+		},
+	},
+	{
+		name:    "join",
+		args:    "[MT形式のファイル・ディレクトリ...]",
+		summary: "エントリーごとのMT形式のファイルを1つのエクスポートにまとめる",
+		setup: func(fs *flag.FlagSet, v *flagValues) {
+			v.addParseFlags(fs)
+			fs.StringVar(&v.output, "output", defaultJoinOutput, "出力するMT形式のファイル")
+			fs.StringVar(&v.mtsDir, "mts-dir", v.mtsDir, "入力を省略した場合に読み込むディレクトリ")
+		},
+		check: func(cfg *config) error {
+			if len(cfg.inputs) == 0 {
+				cfg.inputs = []string{cfg.mtsDir}
+			}
+			return nil
+		},
+		run: func(cfg config) error {
+			return runJoin(cfg.inputs, cfg.output, cfg.parse)
+		},
+	},
+	{
+		name:    "split",
+		args:    "エクスポートファイル...",
+		summary: "エクスポートを件数・サイズの上限ごとの複数のファイルに分割する",
+		setup: func(fs *flag.FlagSet, v *flagValues) {
+			v.addInputFlags(fs)
+			fs.StringVar(&v.output, "output", defaultSplitOutput, "分割したファイルを出力するディレクトリ")
+			fs.IntVar(&v.maxEntries, "max-entries", v.maxEntries, "1ファイルに含めるエントリーの最大件数")
+			fs.StringVar(&v.maxSize, "max-size", v.maxSize, "1ファイルの最大サイズ (例: 512KB, 5MB)")
+		},
+		check: func(cfg *config) error {
+			if cfg.maxEntries == 0 && cfg.maxBytes == 0 {
+				return errors.New("-max-entries か -max-size を指定してください")
+			}
+			return requireInputs(cfg)
+		},
+		run: runSplit,
+	},
+}
+
+// findCommand 名前からサブコマンドを探す
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// printUsage コマンドの一覧を含む全体の使い方を表示
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, usageText)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(tw, "  %s\t%s\n", "help", "コマンドの使い方を表示する")
+	tw.Flush()
+	fmt.Fprintln(w, "\n各コマンドのオプションは mttohmd <コマンド> -h で確認できます")
+}
+
+// config コマンドラインで指定された設定
+type config struct {
+	inputs []string
+	output string
+
+	// convertの出力先と出力する形式
+	mtsDir  string
+	mdsDir  string
+	writeMT bool
+	writeMD bool
+
+	// 対象にするエントリーの範囲（limitが0なら全件）
+	offset     int
+	limit      int
+	skipDrafts bool
+
+	// showで表示するエントリー
+	index    int
+	basename string
+
+	parse            parseOptions
+	policy           merger.Policy
+	profile          generator.Profile
+	layout           generator.Layout
+	filenameTemplate *generator.FilenameTemplate
+	mdOptions        converter.Options
+	trackbacksJSON   bool
+
+	// splitの上限（0なら制限なし）
+	maxEntries int
+	maxBytes   int
+}
+
+// entryRange n件のエントリーのうち -offset と -limit で対象にする範囲 [start, end) を返す
+// listで使う範囲で、convertも解析しながら同じ規則で数える。listに表示する番号（showの -index）は start+1 から始まる
+func (c config) entryRange(n int) (start, end int) {
+	start = min(c.offset, n)
+	end = n
+	if c.limit > 0 {
+		end = min(start+c.limit, end)
+	}
+	return start, end
+}
+
+// flagValues オプションの値（コマンドで登録しなかったオプションは既定値のまま）
+type flagValues struct {
+	inputs     stringList
+	output     string
+	mtsDir     string
+	mdsDir     string
+	mtOnly     bool
+	mdOnly     bool
+	offset     int
+	limit      int
+	index      int
+	basename   string
+	maxEntries int
+	maxSize    string
+	trackbacks string
+	strict     bool
+	timezone   string
+	skipDrafts bool
+	encoding   string
+	filename   string
+	profile    string
+	layout     string
+	slug       bool
+	policy     string
+}
+
+// defaultFlagValues オプションの既定値
+func defaultFlagValues() flagValues {
+	return flagValues{
+		mtsDir:     "mts",
+		mdsDir:     "mds",
+		trackbacks: "none",
+		timezone:   "Local",
+		encoding:   "auto",
+		profile:    "portable",
+		layout:     "flat",
+		policy:     "newest",
+	}
+}
+
+// addParseFlags MT形式のファイルの解析に関するオプションを登録
+func (v *flagValues) addParseFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&v.strict, "strict", v.strict, "解析中の問題をエラーとして扱い、最初の問題で中止する")
+	fs.StringVar(&v.timezone, "timezone", v.timezone, "DATEを解釈するタイムゾーン (例: Asia/Tokyo, UTC)")
+	fs.StringVar(&v.encoding, "encoding", v.encoding, "入力ファイルの文字コード (auto, utf-8, shift_jis, euc-jp)")
+}
+
+// addInputFlags エクスポートファイルの読み込みに関するオプションを登録
+func (v *flagValues) addInputFlags(fs *flag.FlagSet) {
+	fs.Var(&v.inputs, "input", "入力ファイル (繰り返し指定可、引数で指定したファイルの前に読み込む)")
+	v.addParseFlags(fs)
+	fs.BoolVar(&v.skipDrafts, "skip-drafts", v.skipDrafts, "下書き (STATUS: Draft) のエントリーを対象にしない")
+	fs.StringVar(&v.policy, "merge-policy", v.policy, "複数の入力で重複したエントリーの扱い (newest: 最新のDATEのエントリーを含むエクスポートを優先、同じなら後に指定した方, longest: 本文が長い方を優先)")
+}
+
+// addOutputDirFlags convertの出力先と出力する形式のオプションを登録
+func (v *flagValues) addOutputDirFlags(fs *flag.FlagSet) {
+	fs.StringVar(&v.mtsDir, "mts-dir", v.mtsDir, "エントリーごとのMT形式のファイルを出力するディレクトリ")
+	fs.StringVar(&v.mdsDir, "mds-dir", v.mdsDir, "エントリーごとのMarkdownファイルを出力するディレクトリ")
+	fs.BoolVar(&v.mtOnly, "mt-only", v.mtOnly, "MT形式のファイルのみ出力する")
+	fs.BoolVar(&v.mdOnly, "md-only", v.mdOnly, "Markdownファイルのみ出力する")
+}
+
+// addRangeFlags 対象にするエントリーの範囲のオプションを登録
+func (v *flagValues) addRangeFlags(fs *flag.FlagSet) {
+	fs.IntVar(&v.offset, "offset", v.offset, "先頭から読み飛ばすエントリーの件数")
+	fs.IntVar(&v.limit, "limit", v.limit, "対象にするエントリーの最大件数 (0なら全件)")
+}
+
+// addFilenameFlags 出力ファイル名に関するオプションを登録
+func (v *flagValues) addFilenameFlags(fs *flag.FlagSet) {
+	fs.StringVar(&v.filename, "filename", v.filename, "出力ファイル名のテンプレート (例: {{.Year}}/{{.Month}}/{{.Slug}}.md, 未指定なら 日付_タイトル.md)\n使えるフィールド: .Year .Month .Day .Hour .Minute .Second .Slug .RomajiSlug .Basename .Title .Category .Index")
+	fs.StringVar(&v.profile, "filename-profile", v.profile, "ファイル名に使えない文字などの扱い (portable: Windows・macOS・Linuxで使える名前, posix: 「/」と制御文字のみ置き換え, strict: 記号も置き換え)")
+	fs.StringVar(&v.layout, "layout", v.layout, "出力先のディレクトリ構成 (flat: 出力ディレクトリの直下, year: 年ごと, year-month: 年・月ごと, category: 主カテゴリーごと)")
+}
+
+// addMarkdownFlags Markdownへの変換に関するオプションを登録
+func (v *flagValues) addMarkdownFlags(fs *flag.FlagSet) {
+	fs.StringVar(&v.trackbacks, "trackbacks", v.trackbacks, "トラックバックの出力方法 (none: 出力しない, section: 記事末尾に追加, json: サイドカーJSONファイル)")
+	fs.BoolVar(&v.slug, "slug", v.slug, "タイトルから生成したローマ字のスラッグをMarkdownのフロントマターに「Slug」として出力する")
+}
+
+// stringList 繰り返し指定できる文字列のオプション
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// parseArgs コマンドライン引数からサブコマンドを選び、オプションを解析して設定を検証する
+// 使い方を表示した場合はflag.ErrHelpを返す
+func parseArgs(args []string, stderr io.Writer) (command, config, error) {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return command{}, config{}, errors.New("コマンドを指定してください")
+	}
+
+	name, args := args[0], args[1:]
+	switch name {
+	case "-h", "-help", "--help":
+		printUsage(stderr)
+		return command{}, config{}, flag.ErrHelp
+	case "help":
+		if len(args) == 0 {
+			printUsage(stderr)
+			return command{}, config{}, flag.ErrHelp
+		}
+		// help <コマンド> は <コマンド> -h と同じ
+		name, args = args[0], []string{"-h"}
+	}
+
+	cmd, ok := findCommand(name)
+	if !ok {
+		return command{}, config{}, fmt.Errorf("不明なコマンドです: %s", name)
+	}
+
+	fs := flag.NewFlagSet("mttohmd "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "使い方: mttohmd %s [オプション] %s\n\n%s\n\nオプション:\n", cmd.name, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+
+	v := defaultFlagValues()
+	cmd.setup(fs, &v)
+	if err := fs.Parse(args); err != nil {
+		return cmd, config{}, err
+	}
+
+	cfg, err := v.config(fs.Args())
+	if err == nil && cmd.check != nil {
+		err = cmd.check(&cfg)
+	}
+	return cmd, cfg, err
+}
+
+// requireInputs 入力ファイルが指定されているか確認
+func requireInputs(cfg *config) error {
+	if len(cfg.inputs) == 0 {
+		return errors.New("入力ファイルを指定してください")
+	}
+	return nil
+}
+
+// checkShow showで表示するエントリーの指定を確認
+func checkShow(cfg *config) error {
+	if cfg.index == 0 && cfg.basename == "" {
+		return errors.New("-index か -basename で表示するエントリーを指定してください")
+	}
+	if cfg.index != 0 && cfg.basename != "" {
+		return errors.New("-index と -basename は同時に指定できません")
+	}
+	if cfg.index < 0 {
+		return fmt.Errorf("不正な -index の値です: %d", cfg.index)
+	}
+	if cfg.trackbacksJSON {
+		return errors.New("showでは -trackbacks json は使えません")
+	}
+	return requireInputs(cfg)
+}
+
+// config オプションの値を検証して設定に変換
+func (v flagValues) config(args []string) (config, error) {
+	cfg := config{
+		inputs:     append(v.inputs, args...),
+		output:     v.output,
+		mtsDir:     v.mtsDir,
+		mdsDir:     v.mdsDir,
+		writeMT:    !v.mdOnly,
+		writeMD:    !v.mtOnly,
+		offset:     v.offset,
+		limit:      v.limit,
+		skipDrafts: v.skipDrafts,
+		index:      v.index,
+		basename:   v.basename,
+		maxEntries: v.maxEntries,
+	}
+
+	if v.mtOnly && v.mdOnly {
+		return config{}, errors.New("-mt-only と -md-only は同時に指定できません")
+	}
+	if cfg.offset < 0 {
+		return config{}, fmt.Errorf("不正な -offset の値です: %d", cfg.offset)
+	}
+	if cfg.limit < 0 {
+		return config{}, fmt.Errorf("不正な -limit の値です: %d", cfg.limit)
+	}
+	if cfg.maxEntries < 0 {
+		return config{}, fmt.Errorf("不正な -max-entries の値です: %d", cfg.maxEntries)
+	}
+	if v.maxSize != "" {
+		maxBytes, err := parseSize(v.maxSize)
+		if err != nil {
+			return config{}, fmt.Errorf("不正な -max-size の値です: %v", err)
+		}
+		cfg.maxBytes = maxBytes
+	}
+
+	switch v.trackbacks {
+	case "none", "section", "json":
+	default:
+		return config{}, fmt.Errorf("不正な -trackbacks の値です: %s", v.trackbacks)
+	}
+	cfg.trackbacksJSON = v.trackbacks == "json"
+	cfg.mdOptions = converter.Options{Trackbacks: v.trackbacks == "section", Slug: v.slug}
+
+	location, err := time.LoadLocation(v.timezone)
+	if err != nil {
+		return config{}, fmt.Errorf("不正な -timezone の値です: %s", v.timezone)
+	}
+	encoding, err := entry.ParseEncoding(v.encoding)
+	if err != nil {
+		return config{}, fmt.Errorf("不正な -encoding の値です: %s", v.encoding)
+	}
+	cfg.parse = parseOptions{strict: v.strict, location: location, encoding: encoding}
+
+	if cfg.policy, err = merger.ParsePolicy(v.policy); err != nil {
+		return config{}, fmt.Errorf("不正な -merge-policy の値です: %s", v.policy)
+	}
+	if cfg.profile, err = generator.ParseProfile(v.profile); err != nil {
+		return config{}, fmt.Errorf("不正な -filename-profile の値です: %s", v.profile)
+	}
+	if cfg.layout, err = generator.ParseLayout(v.layout); err != nil {
+		return config{}, fmt.Errorf("不正な -layout の値です: %s", v.layout)
+	}
+	if v.filename != "" {
+		if cfg.filenameTemplate, err = generator.ParseFilenameTemplate(v.filename); err != nil {
+			return config{}, fmt.Errorf("不正な -filename の値です: %v", err)
+		}
+		cfg.filenameTemplate.Profile = cfg.profile
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+
+	"mttohmd/entry"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		command string // 選ばれるコマンド（空ならコマンドなし）
+		err     string // エラーに含まれる文字列（空ならエラーなし）
+		help    bool   // 使い方を表示してflag.ErrHelpを返す
+	}{
+		{name: "引数なし", args: nil, err: "コマンドを指定してください"},
+		{name: "-h", args: []string{"-h"}, help: true},
+		{name: "help", args: []string{"help"}, help: true},
+		{name: "help <コマンド>", args: []string{"help", "list"}, command: "list", help: true},
+		{name: "help 不明なコマンド", args: []string{"help", "unknown"}, err: "不明なコマンドです: unknown"},
+		{name: "不明なコマンド", args: []string{"unknown", "blog.txt"}, err: "不明なコマンドです: unknown"},
+		{name: "convert", args: []string{"convert", "blog.txt"}, command: "convert"},
+		{name: "入力なし", args: []string{"convert"}, command: "convert", err: "入力ファイルを指定してください"},
+		{name: "不明なオプション", args: []string{"list", "-unknown", "blog.txt"}, command: "list", err: "flag provided but not defined"},
+		{name: "他のコマンドのオプション", args: []string{"list", "-mt-only", "blog.txt"}, command: "list", err: "flag provided but not defined"},
+		{name: "-mt-only と -md-only", args: []string{"convert", "-mt-only", "-md-only", "blog.txt"}, command: "convert", err: "同時に指定できません"},
+		{name: "負の -offset", args: []string{"convert", "-offset", "-1", "blog.txt"}, command: "convert", err: "不正な -offset の値です: -1"},
+		{name: "負の -limit", args: []string{"list", "-limit", "-5", "blog.txt"}, command: "list", err: "不正な -limit の値です: -5"},
+		{name: "数値でない -limit", args: []string{"list", "-limit", "x", "blog.txt"}, command: "list", err: "invalid value"},
+		{name: "showの指定なし", args: []string{"show", "blog.txt"}, command: "show", err: "-index か -basename"},
+		{name: "show -index", args: []string{"show", "-index", "3", "blog.txt"}, command: "show"},
+		{name: "show -basename", args: []string{"show", "-basename", "2023/01/15/120000", "blog.txt"}, command: "show"},
+		{name: "show -index と -basename", args: []string{"show", "-index", "1", "-basename", "x", "blog.txt"}, command: "show", err: "同時に指定できません"},
+		{name: "show 負の -index", args: []string{"show", "-index", "-1", "blog.txt"}, command: "show", err: "不正な -index の値です: -1"},
+		{name: "show -trackbacks json", args: []string{"show", "-index", "1", "-trackbacks", "json", "blog.txt"}, command: "show", err: "-trackbacks json"},
+		{name: "splitの上限なし", args: []string{"split", "blog.txt"}, command: "split", err: "-max-entries か -max-size"},
+		{name: "reverseの入力は省略可", args: []string{"reverse"}, command: "reverse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			cmd, _, err := parseArgs(tt.args, &stderr)
+
+			if cmd.name != tt.command {
+				t.Errorf("command = %q, want %q", cmd.name, tt.command)
+			}
+			switch {
+			case tt.help:
+				if err != flag.ErrHelp {
+					t.Errorf("err = %v, want flag.ErrHelp", err)
+				}
+				if !strings.Contains(stderr.String(), "使い方: mttohmd") {
+					t.Errorf("Expected usage, got %q", stderr.String())
+				}
+			case tt.err == "":
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			case err == nil || !strings.Contains(err.Error(), tt.err):
+				t.Errorf("err = %v, want error containing %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseArgsHelpCommand(t *testing.T) {
+	// help <コマンド> は <コマンド> -h と同じく、そのコマンドのオプションを表示する
+	var help, flagHelp bytes.Buffer
+	parseArgs([]string{"help", "show"}, &help)
+	parseArgs([]string{"show", "-h"}, &flagHelp)
+
+	if help.String() != flagHelp.String() {
+		t.Errorf("help show = %q, want %q", help.String(), flagHelp.String())
+	}
+	if !strings.Contains(help.String(), "使い方: mttohmd show") || !strings.Contains(help.String(), "-basename") {
+		t.Errorf("Expected show usage, got %q", help.String())
+	}
+}
+
+func TestParseArgsConfig(t *testing.T) {
+	_, cfg, err := parseArgs([]string{
+		"convert", "-input", "a.txt", "-md-only", "-offset", "2", "-limit", "3", "-mds-dir", "out", "b.txt", "c.txt",
+	}, io.Discard)
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+
+	// -input で指定したファイルは引数のファイルより前に読む
+	if strings.Join(cfg.inputs, ",") != "a.txt,b.txt,c.txt" {
+		t.Errorf("inputs = %v", cfg.inputs)
+	}
+	if cfg.writeMT || !cfg.writeMD {
+		t.Errorf("Expected Markdown only, got writeMT=%v writeMD=%v", cfg.writeMT, cfg.writeMD)
+	}
+	if cfg.offset != 2 || cfg.limit != 3 || cfg.mdsDir != "out" || cfg.mtsDir != "mts" {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+}
+
+func TestFlagValuesConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(v *flagValues)
+		err    string
+	}{
+		{name: "既定値", modify: func(v *flagValues) {}},
+		{name: "-mt-only", modify: func(v *flagValues) { v.mtOnly = true }},
+		{name: "-mt-only と -md-only", modify: func(v *flagValues) { v.mtOnly, v.mdOnly = true, true }, err: "-mt-only と -md-only は同時に指定できません"},
+		{name: "負の -offset", modify: func(v *flagValues) { v.offset = -1 }, err: "不正な -offset の値です"},
+		{name: "負の -limit", modify: func(v *flagValues) { v.limit = -1 }, err: "不正な -limit の値です"},
+		{name: "負の -max-entries", modify: func(v *flagValues) { v.maxEntries = -1 }, err: "不正な -max-entries の値です"},
+		{name: "大きすぎる -max-size", modify: func(v *flagValues) { v.maxSize = "9999999999G" }, err: "不正な -max-size の値です"},
+		{name: "不正な -trackbacks", modify: func(v *flagValues) { v.trackbacks = "inline" }, err: "不正な -trackbacks の値です"},
+		{name: "不正な -timezone", modify: func(v *flagValues) { v.timezone = "Mars/Base" }, err: "不正な -timezone の値です"},
+		{name: "不正な -encoding", modify: func(v *flagValues) { v.encoding = "latin1" }, err: "不正な -encoding の値です"},
+		{name: "不正な -merge-policy", modify: func(v *flagValues) { v.policy = "oldest" }, err: "不正な -merge-policy の値です"},
+		{name: "不正な -layout", modify: func(v *flagValues) { v.layout = "tree" }, err: "不正な -layout の値です"},
+		{name: "不正な -filename", modify: func(v *flagValues) { v.filename = "{{.Unknown" }, err: "不正な -filename の値です"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := defaultFlagValues()
+			tt.modify(&v)
+			_, err := v.config([]string{"blog.txt"})
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("err = %v, want error containing %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCheckShow(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config
+		err  string
+	}{
+		{name: "-index", cfg: config{inputs: []string{"a.txt"}, index: 1}},
+		{name: "-basename", cfg: config{inputs: []string{"a.txt"}, basename: "x"}},
+		{name: "指定なし", cfg: config{inputs: []string{"a.txt"}}, err: "-index か -basename で表示するエントリーを指定してください"},
+		{name: "両方", cfg: config{inputs: []string{"a.txt"}, index: 1, basename: "x"}, err: "-index と -basename は同時に指定できません"},
+		{name: "負の -index", cfg: config{inputs: []string{"a.txt"}, index: -2}, err: "不正な -index の値です: -2"},
+		{name: "-trackbacks json", cfg: config{inputs: []string{"a.txt"}, index: 1, trackbacksJSON: true}, err: "showでは -trackbacks json は使えません"},
+		{name: "入力なし", cfg: config{index: 1}, err: "入力ファイルを指定してください"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkShow(&tt.cfg)
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("err = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSelectEntry(t *testing.T) {
+	entries := []entry.Entry{
+		{Title: "A", Basename: "2023/01/01/000000"},
+		{Title: "B", Basename: "2023/01/02/000000"},
+		{Title: "C"},
+	}
+
+	tests := []struct {
+		name     string
+		index    int
+		basename string
+		expected string // 選ばれるエントリーのタイトル（空ならエラー）
+	}{
+		{name: "先頭", index: 1, expected: "A"},
+		{name: "最後", index: 3, expected: "C"},
+		{name: "範囲外", index: 4},
+		{name: "0番目", index: 0},
+		{name: "BASENAME", basename: "2023/01/02/000000", expected: "B"},
+		{name: "BASENAMEを優先", index: 1, basename: "2023/01/02/000000", expected: "B"},
+		{name: "見つからないBASENAME", basename: "2023/01/03/000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := selectEntry(entries, tt.index, tt.basename)
+			if tt.expected == "" {
+				if err == nil {
+					t.Errorf("Expected error, got %q", e.Title)
+				}
+				return
+			}
+			if err != nil || e.Title != tt.expected {
+				t.Errorf("selectEntry() = %q, %v, want %q", e.Title, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEntryRange(t *testing.T) {
+	tests := []struct {
+		name          string
+		offset, limit int
+		start, end    int
+	}{
+		{name: "全件", offset: 0, limit: 0, start: 0, end: 5},
+		{name: "-limit", offset: 0, limit: 2, start: 0, end: 2},
+		{name: "-offset", offset: 3, limit: 0, start: 3, end: 5},
+		{name: "-offset と -limit", offset: 1, limit: 2, start: 1, end: 3},
+		{name: "件数を超える -limit", offset: 4, limit: 10, start: 4, end: 5},
+		{name: "件数を超える -offset", offset: 8, limit: 2, start: 5, end: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config{offset: tt.offset, limit: tt.limit}
+			start, end := cfg.entryRange(5)
+			if start != tt.start || end != tt.end {
+				t.Errorf("entryRange(5) = %d, %d, want %d, %d", start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestListNumberSelectsSameEntryInShow(t *testing.T) {
+	// listが -offset で読み飛ばしても表示する番号は先頭からの番号で、showの -index でそのまま選べる
+	entries := []entry.Entry{{Title: "A"}, {Title: "B"}, {Title: "C"}, {Title: "D"}}
+	cfg := config{offset: 2, limit: 1}
+
+	start, end := cfg.entryRange(len(entries))
+	if end-start != 1 {
+		t.Fatalf("Expected 1 entry, got %d", end-start)
+	}
+	number := start + 1 // listの「#」列
+	e, err := selectEntry(entries, number, "")
+	if err != nil || e.Title != entries[start].Title {
+		t.Errorf("selectEntry(%d) = %q, %v, want %q", number, e.Title, err, entries[start].Title)
+	}
+}
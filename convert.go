@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mttohmd/converter"
+	"mttohmd/entry"
+	"mttohmd/generator"
+	"mttohmd/input"
+)
+
+// errLimitReached -limit で指定した件数を出力し終えたことを表す
+var errLimitReached = errors.New("出力件数の上限に達しました")
+
+// runConvert MT形式のエクスポートを読み込み、エントリーごとのMT形式とMarkdown形式のファイルに出力
+func runConvert(cfg config) error {
+	// ファイルの存在確認（gzip・zip・標準入力 "-" にも対応）
+	for _, filename := range cfg.inputs {
+		if filename == input.Stdin {
+			continue
+		}
+		if _, err := os.Stat(filename); os.IsNotExist(err) {
+			return fmt.Errorf("%s が見つかりません", filename)
+		}
+	}
+
+	// 出力ディレクトリの作成（出力しない形式のディレクトリは作らない）
+	if cfg.writeMT {
+		if err := os.MkdirAll(cfg.mtsDir, 0755); err != nil {
+			return fmt.Errorf("MTSディレクトリ作成エラー: %w", err)
+		}
+	}
+	if cfg.writeMD {
+		if err := os.MkdirAll(cfg.mdsDir, 0755); err != nil {
+			return fmt.Errorf("MDSディレクトリ作成エラー: %w", err)
+		}
+	}
+
+	// 下書きを除いたエントリーのうち、-offset 件目以降の -limit 件を出力
+	// （実行全体でファイル名の衝突を避ける）。入力が1つのエクスポートであれば解析しながら1件ずつ出力し、
+	// zipに含まれるファイルなど複数のエクスポートがあれば重複を除いてマージしてから出力する
+	names := generator.NewNameRegistry()
+	count := 0
+	seen := 0
+	result, err := walkEntries(cfg, func(e entry.Entry) error {
+		if cfg.limit > 0 && count == cfg.limit {
+			return errLimitReached
+		}
+		seen++
+		if seen <= cfg.offset {
+			return nil
+		}
+		count++
+
+		filename := generator.GenerateFilenameWithProfile(e, cfg.profile)
+		if cfg.filenameTemplate != nil {
+			name, err := cfg.filenameTemplate.Execute(e, seen)
+			if err != nil {
+				return fmt.Errorf("ファイル名生成エラー: %w", err)
+			}
+			filename = name
+		}
+		filename = filepath.FromSlash(names.Reserve(cfg.layout.Path(e, filename, cfg.profile)))
+
+		writeEntry(count, e, filename, cfg)
+		return nil
+	})
+	for _, s := range result.encodings {
+		fmt.Printf("%s: 文字コード %s\n", s.name, s.encoding)
+	}
+	if err != nil && !errors.Is(err, errLimitReached) {
+		return err
+	}
+	if result.merge != nil {
+		printMergeReport(*result.merge)
+	}
+
+	printDiagnostics(result.diagnostics)
+	printCollisions(names.Collisions())
+
+	if result.drafts > 0 {
+		fmt.Printf("下書き %d件を除外しました\n", result.drafts)
+	}
+	if errors.Is(err, errLimitReached) {
+		fmt.Printf("-limit で指定した%d件に達したため、残りのエントリーは出力していません\n", cfg.limit)
+	}
+
+	fmt.Printf("変換完了！ %d個のエントリーを処理しました\n", count)
+	return nil
+}
+
+// writeEntry エントリーを設定に従ってMT形式とMarkdown形式でそれぞれのディレクトリに出力
+func writeEntry(i int, e entry.Entry, filename string, cfg config) {
+	// MT形式でmtsディレクトリに出力
+	if cfg.writeMT {
+		mtFilepath := filepath.Join(cfg.mtsDir, generator.MTFilename(filename))
+		mtContent := generator.GenerateMTContent(e)
+
+		if err := writeFile(mtFilepath, []byte(mtContent)); err != nil {
+			fmt.Printf("MTファイル書き込みエラー (%s): %v\n", mtFilepath, err)
+		} else {
+			fmt.Printf("%d: %s を作成しました\n", i, mtFilepath)
+		}
+	}
+
+	if !cfg.writeMD {
+		return
+	}
+
+	// Markdown形式でmdsディレクトリに出力
+	mdFilepath := filepath.Join(cfg.mdsDir, filename)
+	mdContent := converter.ToMarkdownWithOptions(e, cfg.mdOptions)
+
+	if err := writeFile(mdFilepath, []byte(mdContent)); err != nil {
+		fmt.Printf("Markdownファイル書き込みエラー (%s): %v\n", mdFilepath, err)
+	} else {
+		fmt.Printf("%d: %s を作成しました\n", i, mdFilepath)
+	}
+
+	// トラックバックをサイドカーJSONとしてmdsディレクトリに出力
+	if cfg.trackbacksJSON && len(e.Pings) > 0 {
+		jsonFilepath := strings.TrimSuffix(mdFilepath, ".md") + ".trackbacks.json"
+		jsonContent, err := converter.TrackbacksJSON(e)
+		if err == nil {
+			err = writeFile(jsonFilepath, jsonContent)
+		}
+		if err != nil {
+			fmt.Printf("トラックバックJSON書き込みエラー (%s): %v\n", jsonFilepath, err)
+		} else {
+			fmt.Printf("%d: %s を作成しました\n", i, jsonFilepath)
+		}
+	}
+}
@@ -1,13 +1,19 @@
 package converter
 
 import (
+	"encoding/json"
 	"html"
 	"regexp"
 	"strings"
+	"time"
 
 	"mttohmd/entry"
+	"mttohmd/slug"
 )
 
+// moreMarker はてなブログの「続きを読む」記法
+const moreMarker = "<!-- more -->"
+
 var (
 	// ASIN詳細タグ用の正規表現
 	asinDetailWithPTagRegex = regexp.MustCompile(`(?s)<p><div class="hatena-asin-detail">.*?href="https://www\.amazon\.co\.jp/dp/([A-Z0-9]+)[^"]*".*?</div></div></p>`)
@@ -44,32 +50,71 @@ var (
 	h6Regex = regexp.MustCompile(`<h6[^>]*>(.*?)</h6>`)
 )
 
+// Options Markdown変換のオプション
+type Options struct {
+	// Trackbacks トラックバックを記事末尾に「Trackbacks」セクションとして出力する
+	Trackbacks bool
+	// Slug タイトルから生成したローマ字のスラッグをフロントマターに「Slug」として出力する
+	Slug bool
+}
+
 // ToMarkdown エントリーをHatena Blog形式のMarkdownに変換
 func ToMarkdown(e entry.Entry) string {
+	return ToMarkdownWithOptions(e, Options{})
+}
+
+// ToMarkdownWithOptions オプションを指定してエントリーをHatena Blog形式のMarkdownに変換
+func ToMarkdownWithOptions(e entry.Entry, opts Options) string {
 	var md strings.Builder
 
 	// はてなブログ用のメタデータ（フロントマター形式）
 	md.WriteString("---\n")
 	md.WriteString("Title: ")
-	md.WriteString(e.Title)
+	md.WriteString(quoteYAML(e.Title))
 	md.WriteString("\n")
 
-	if e.Category != "" {
+	if len(e.Categories) > 0 {
 		md.WriteString("Category:\n")
-		categories := strings.Split(e.Category, ",")
-		for _, cat := range categories {
-			cat = strings.TrimSpace(cat)
-			if cat != "" {
-				md.WriteString("- ")
-				md.WriteString(cat)
-				md.WriteString("\n")
-			}
+		for _, cat := range e.Categories {
+			md.WriteString("- ")
+			md.WriteString(quoteYAML(cat.Name))
+			md.WriteString("\n")
 		}
 	}
 
-	if e.Date != "" {
+	// 解析済みの日時があればRFC3339形式、なければ元の表記のまま出力
+	if !e.DateTime.IsZero() {
 		md.WriteString("Date: ")
-		md.WriteString(e.Date)
+		md.WriteString(e.DateTime.Format(time.RFC3339))
+		md.WriteString("\n")
+	} else if e.Date != "" {
+		md.WriteString("Date: ")
+		md.WriteString(quoteYAML(e.Date))
+		md.WriteString("\n")
+	}
+
+	// 下書きとURLは、reverseで読み戻したときに失われないよう同期ツールと同じキーで出力
+	if e.Basename != "" {
+		md.WriteString("CustomPath: ")
+		md.WriteString(quoteYAML(e.Basename))
+		md.WriteString("\n")
+	}
+	if e.Status == entry.StatusDraft {
+		md.WriteString("Draft: true\n")
+	}
+
+	if opts.Slug {
+		if s := slug.Generate(e); s != "" {
+			md.WriteString("Slug: ")
+			md.WriteString(s)
+			md.WriteString("\n")
+		}
+	}
+
+	// 概要は1行にまとめて出力
+	if excerpt := strings.Join(strings.Fields(e.Excerpt), " "); excerpt != "" {
+		md.WriteString("Excerpt: ")
+		md.WriteString(quoteYAML(excerpt))
 		md.WriteString("\n")
 	}
 
@@ -77,9 +122,17 @@ func ToMarkdown(e entry.Entry) string {
 
 	// 記事本文
 	// MovableType形式からMarkdown/HTML混在形式へ変換
-	body := convertMTToMarkdown(e.Body)
+	body := convertBody(e.Body, e.ConvertBreaks)
 	md.WriteString(body)
 
+	// 追記がある場合は「続きを読む」記法の後に出力
+	if e.ExtendedBody != "" {
+		md.WriteString("\n\n")
+		md.WriteString(moreMarker)
+		md.WriteString("\n\n")
+		md.WriteString(convertBody(e.ExtendedBody, e.ConvertBreaks))
+	}
+
 	// 画像がある場合は記事の最後に追加
 	if e.ImageURL != "" {
 		md.WriteString("\n\n")
@@ -90,9 +143,73 @@ func ToMarkdown(e entry.Entry) string {
 		md.WriteString(")")
 	}
 
+	if opts.Trackbacks && len(e.Pings) > 0 {
+		md.WriteString("\n\n")
+		md.WriteString(trackbacksSection(e.Pings))
+	}
+
 	return md.String()
 }
 
+// trackbacksSection トラックバック一覧をMarkdownのセクションに変換
+func trackbacksSection(pings []entry.Ping) string {
+	var md strings.Builder
+	md.WriteString("## Trackbacks\n")
+	for _, p := range pings {
+		md.WriteString("\n- ")
+		title := p.Title
+		if title == "" {
+			title = p.URL
+		}
+		if p.URL != "" {
+			md.WriteString("[" + title + "](" + p.URL + ")")
+		} else {
+			md.WriteString(title)
+		}
+		if p.BlogName != "" {
+			md.WriteString(" - ")
+			md.WriteString(p.BlogName)
+		}
+		if p.Date != "" {
+			md.WriteString(" (" + p.Date + ")")
+		}
+	}
+	return md.String()
+}
+
+// TrackbacksJSON トラックバック一覧をサイドカーファイル用のJSONに変換
+func TrackbacksJSON(e entry.Entry) ([]byte, error) {
+	pings := e.Pings
+	if pings == nil {
+		pings = []entry.Ping{}
+	}
+	return json.MarshalIndent(pings, "", "  ")
+}
+
+// convertBody はCONVERT BREAKSの指定に応じて本文をMarkdown形式に変換する
+func convertBody(body string, format entry.ConvertBreaks) string {
+	switch {
+	case format.IsMarkdown():
+		// Markdownで書かれた本文はHTML変換をせず改行の正規化のみ
+		result := strings.ReplaceAll(body, "\r\n", "\n")
+		result = strings.ReplaceAll(result, "\r", "\n")
+		return strings.TrimSpace(result)
+	case format.ConvertsLineBreaks():
+		// 改行がそのまま改行として表示される本文は段落内の改行をMarkdownの強制改行にする
+		return hardLineBreaks(convertMTToMarkdown(body))
+	}
+	return convertMTToMarkdown(body)
+}
+
+// hardLineBreaks は段落内の改行を行末の2つの空白による強制改行に変換する
+func hardLineBreaks(text string) string {
+	paragraphs := strings.Split(text, "\n\n")
+	for i, p := range paragraphs {
+		paragraphs[i] = strings.ReplaceAll(p, "\n", "  \n")
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
 // convertMTToMarkdown はMovableType形式のテキストをMarkdown形式に変換する
 func convertMTToMarkdown(body string) string {
 	// 基本的な変換処理
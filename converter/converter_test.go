@@ -3,6 +3,7 @@ package converter
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"mttohmd/entry"
 )
@@ -15,7 +16,11 @@ func TestToMarkdown(t *testing.T) {
 		Basename: "test_blog_post",
 		Status:   "Publish",
 		Date:     "01/15/2023 12:00:00 AM",
-		Category: "Technology, Go, Testing",
+		Categories: []entry.Category{
+			{Name: "Technology"},
+			{Name: "Go"},
+			{Name: "Testing"},
+		},
 		Body:     "これはテスト用の本文です。\n<strong>太字</strong>と<code>&lt;C-Enter</code>のテストも含まれます。",
 		ImageURL: "https://example.com/test.jpg",
 	}
@@ -308,3 +313,168 @@ func TestConvertHTMLToMarkdownHatenaASIN(t *testing.T) {
 		})
 	}
 }
+
+func TestToMarkdownWithExtendedBodyAndExcerpt(t *testing.T) {
+	testEntry := entry.Entry{
+		Title:        "Extended Post",
+		Body:         "本文です。",
+		ExtendedBody: "<strong>追記</strong>です。",
+		Excerpt:      "概要の\n説明です。",
+	}
+
+	result := ToMarkdown(testEntry)
+
+	if !strings.Contains(result, "Excerpt: 概要の 説明です。\n---\n") {
+		t.Error("Expected excerpt in frontmatter not found")
+	}
+	if !strings.Contains(result, "本文です。\n\n<!-- more -->\n\n**追記**です。") {
+		t.Errorf("Expected extended body after more marker, got %q", result)
+	}
+}
+
+func TestToMarkdownWithoutExtendedBody(t *testing.T) {
+	testEntry := entry.Entry{
+		Title: "Simple Post",
+		Body:  "本文のみです。",
+	}
+
+	result := ToMarkdown(testEntry)
+
+	if strings.Contains(result, "<!-- more -->") {
+		t.Error("More marker should not be present")
+	}
+	if strings.Contains(result, "Excerpt:") {
+		t.Error("Excerpt should not be present")
+	}
+}
+
+func TestToMarkdownWithTrackbacks(t *testing.T) {
+	testEntry := entry.Entry{
+		Title: "Trackback Post",
+		Body:  "本文です。",
+		Pings: []entry.Ping{
+			{
+				Title:    "言及した記事",
+				URL:      "https://example.com/entry/1",
+				BlogName: "Example Blog",
+				Date:     "01/04/2023 09:00:00 AM",
+			},
+		},
+	}
+
+	// デフォルトではトラックバックを出力しない
+	if strings.Contains(ToMarkdown(testEntry), "## Trackbacks") {
+		t.Error("Trackbacks section should not be present by default")
+	}
+
+	result := ToMarkdownWithOptions(testEntry, Options{Trackbacks: true})
+	expected := "本文です。\n\n## Trackbacks\n\n- [言及した記事](https://example.com/entry/1) - Example Blog (01/04/2023 09:00:00 AM)"
+	if !strings.HasSuffix(result, expected) {
+		t.Errorf("ToMarkdownWithOptions() = %q, want suffix %q", result, expected)
+	}
+}
+
+func TestTrackbacksJSON(t *testing.T) {
+	testEntry := entry.Entry{
+		Title: "Trackback Post",
+		Pings: []entry.Ping{
+			{Title: "記事", URL: "https://example.com/", BlogName: "Blog"},
+		},
+	}
+
+	result, err := TrackbacksJSON(testEntry)
+	if err != nil {
+		t.Fatalf("TrackbacksJSON failed: %v", err)
+	}
+
+	expected := "[\n  {\n    \"title\": \"記事\",\n    \"url\": \"https://example.com/\",\n    \"blog_name\": \"Blog\"\n  }\n]"
+	if string(result) != expected {
+		t.Errorf("TrackbacksJSON() = %q, want %q", result, expected)
+	}
+
+	empty, err := TrackbacksJSON(entry.Entry{})
+	if err != nil {
+		t.Fatalf("TrackbacksJSON failed: %v", err)
+	}
+	if string(empty) != "[]" {
+		t.Errorf("TrackbacksJSON() = %q, want %q", empty, "[]")
+	}
+}
+
+func TestToMarkdownWithDateTime(t *testing.T) {
+	testEntry := entry.Entry{
+		Title:    "Dated Post",
+		Date:     "01/15/2023 03:00:00 PM",
+		DateTime: time.Date(2023, 1, 15, 15, 0, 0, 0, time.FixedZone("JST", 9*60*60)),
+		Body:     "本文",
+	}
+
+	result := ToMarkdown(testEntry)
+
+	if !strings.Contains(result, "Date: 2023-01-15T15:00:00+09:00\n") {
+		t.Errorf("Expected RFC3339 date in frontmatter, got %q", result)
+	}
+}
+
+func TestToMarkdownWithSlug(t *testing.T) {
+	testEntry := entry.Entry{Title: "はじめてのGo", Body: "本文"}
+
+	// デフォルトではスラッグを出力しない
+	if strings.Contains(ToMarkdown(testEntry), "Slug:") {
+		t.Error("Slug should not be present by default")
+	}
+
+	result := ToMarkdownWithOptions(testEntry, Options{Slug: true})
+	expected := "---\nTitle: はじめてのGo\nSlug: hajimeteno-go\n---\n\n"
+	if !strings.HasPrefix(result, expected) {
+		t.Errorf("ToMarkdownWithOptions() = %q, want prefix %q", result, expected)
+	}
+
+	// スラッグを生成できない場合は出力しない
+	if result := ToMarkdownWithOptions(entry.Entry{Title: "日本語"}, Options{Slug: true}); strings.Contains(result, "Slug:") {
+		t.Errorf("Expected no slug, got %q", result)
+	}
+}
+
+func TestConvertBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		format   entry.ConvertBreaks
+		expected string
+	}{
+		{
+			name:     "HTML（改行変換なし）",
+			input:    "<p>1行目\n2行目</p>",
+			format:   entry.ConvertBreaksNone,
+			expected: "1行目\n2行目",
+		},
+		{
+			name:     "未指定はHTMLとして扱う",
+			input:    "<strong>太字</strong>",
+			format:   "",
+			expected: "**太字**",
+		},
+		{
+			name:     "改行変換あり",
+			input:    "1行目\n2行目\n\n次の段落",
+			format:   entry.ConvertBreaksDefault,
+			expected: "1行目  \n2行目\n\n次の段落",
+		},
+		{
+			name:     "Markdown",
+			input:    "# 見出し\r\n\r\n<strong>そのまま</strong>\n",
+			format:   entry.ConvertBreaksMarkdown,
+			expected: "# 見出し\n\n<strong>そのまま</strong>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := convertBody(tt.input, tt.format)
+			if result != tt.expected {
+				t.Errorf("convertBody() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,450 @@
+package converter
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Markdownのブロック要素判定用の正規表現（見出し・引用・リストは記号の後に内容がある場合のみ）
+	mdFenceRegex      = regexp.MustCompile("^ {0,3}(`{3,})[ \t]*([^ \t`]*)[ \t]*$")
+	mdHeadingRegex    = regexp.MustCompile(`^ {0,3}(#{1,6})[ \t]+(\S.*?)(?:[ \t]+#+)?[ \t]*$`)
+	mdBlockquoteRegex = regexp.MustCompile(`^ {0,3}> ?`)
+	mdListItemRegex   = regexp.MustCompile(`^ {0,3}([-*+]|\d{1,9}\.)[ \t]+(\S.*)$`)
+	mdHTMLBlockRegex  = regexp.MustCompile(`^ {0,3}<(?:/?([A-Za-z][A-Za-z0-9-]*)(?:[\s/>]|$)|!--)`)
+
+	// Markdownのインライン要素判定用の正規表現
+	mdInlineTagRegex = regexp.MustCompile(`^(?:<[A-Za-z][A-Za-z0-9-]*(?:\s+[A-Za-z_:][-A-Za-z0-9_.:]*(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?)*\s*/?>|</[A-Za-z][A-Za-z0-9-]*\s*>|<!--[\s\S]*?-->)`)
+	mdEntityRegex    = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]{1,31});`)
+)
+
+// htmlBlockTags 行頭にあればHTMLブロックとしてそのまま出力するタグ
+var htmlBlockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "audio": true, "blockquote": true, "center": true,
+	"details": true, "dialog": true, "dd": true, "div": true, "dl": true, "dt": true, "fieldset": true,
+	"figcaption": true, "figure": true, "footer": true, "form": true, "h1": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "header": true, "hr": true, "iframe": true, "li": true, "main": true,
+	"nav": true, "ol": true, "p": true, "pre": true, "script": true, "section": true, "style": true,
+	"summary": true, "table": true, "tbody": true, "td": true, "textarea": true, "tfoot": true, "th": true,
+	"thead": true, "tr": true, "ul": true, "video": true,
+}
+
+// htmlRawTags 終了タグまで空行を含めてそのまま出力するタグ
+var htmlRawTags = map[string]bool{"pre": true, "script": true, "style": true, "textarea": true}
+
+// textEscaper 本文中のHTMLの特殊文字をエスケープ（引用符はそのまま残す）
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// MarkdownToHTML はてなブログのMarkdownで書かれた本文をHTMLに変換
+//
+// 対応するのはToMarkdownが出力する記法を中心とした、はてなブログのMarkdownの一部のみ:
+//   - 段落（はてなブログと同じく段落内の改行は<br />、行末の2つの空白による強制改行も同じ）
+//   - 「#」〜「######」の見出し、「>」の引用
+//   - 「-」「*」「+」「1.」で始まる入れ子のないリスト（字下げした行は項目の続き）
+//   - 「```」で囲まれたコードブロック
+//   - 行頭のHTMLブロック・インラインのHTMLタグ・はてな記法（[asin:...]など）はそのまま
+//   - **太字**・*斜体*・`コード`・[リンク](URL)・![画像](URL)・バックスラッシュによるエスケープ
+//
+// 表・字下げによるコードブロック・入れ子のリスト・下線による見出し・区切り線などには対応せず、
+// 記号だけの行（「#」「-」「>」など）と同じく段落のテキストとして出力する
+func MarkdownToHTML(md string) string {
+	md = strings.ReplaceAll(md, "\r\n", "\n")
+	md = strings.ReplaceAll(md, "\r", "\n")
+	md = strings.ReplaceAll(md, "\t", "    ")
+	return renderBlocks(strings.Split(md, "\n"))
+}
+
+// renderBlocks 行の並びをブロック要素ごとにHTMLに変換
+func renderBlocks(lines []string) string {
+	var blocks []string
+	for i := 0; i < len(lines); {
+		var block string
+		switch line := lines[i]; {
+		case isBlank(line):
+			i++
+			continue
+		case mdFenceRegex.MatchString(line):
+			block, i = renderFencedCode(lines, i)
+		case mdHeadingRegex.MatchString(line):
+			m := mdHeadingRegex.FindStringSubmatch(line)
+			block = fmt.Sprintf("<h%d>%s</h%d>", len(m[1]), renderInline(m[2]), len(m[1]))
+			i++
+		case isBlockquoteStart(line):
+			block, i = renderBlockquote(lines, i)
+		case mdListItemRegex.MatchString(line):
+			block, i = renderList(lines, i)
+		case isHTMLBlockStart(line):
+			block, i = renderHTMLBlock(lines, i)
+		default:
+			block, i = renderParagraph(lines, i)
+		}
+		blocks = append(blocks, block)
+	}
+	return strings.Join(blocks, "\n")
+}
+
+// startsBlock 段落の途中で新しいブロックを始める行かどうか
+func startsBlock(line string) bool {
+	return mdFenceRegex.MatchString(line) || mdHeadingRegex.MatchString(line) || isBlockquoteStart(line) ||
+		mdListItemRegex.MatchString(line) || isHTMLBlockStart(line)
+}
+
+// renderParagraph 空行か別のブロックの開始行までを1つの段落として変換
+func renderParagraph(lines []string, i int) (string, int) {
+	var para []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if isBlank(line) || (len(para) > 0 && startsBlock(line)) {
+			break
+		}
+		para = append(para, strings.TrimSpace(line))
+	}
+	return "<p>" + renderLines(para) + "</p>", i
+}
+
+// renderLines 段落内の行をインライン要素に変換して<br />でつなぐ
+// （行末のバックスラッシュによる強制改行も通常の改行と同じ扱い）
+func renderLines(lines []string) string {
+	for j, line := range lines {
+		if j < len(lines)-1 {
+			line = strings.TrimSuffix(line, "\\")
+		}
+		lines[j] = line
+	}
+	return strings.ReplaceAll(renderInline(strings.Join(lines, "\n")), "\n", "<br />\n")
+}
+
+// renderFencedCode ```で囲まれたコードブロックを変換（閉じられていなければ本文の最後まで）
+func renderFencedCode(lines []string, i int) (string, int) {
+	m := mdFenceRegex.FindStringSubmatch(lines[i])
+	fence, lang := m[1], m[2]
+
+	var code []string
+	for i++; i < len(lines); i++ {
+		if closing := strings.TrimSpace(lines[i]); strings.HasPrefix(closing, fence) && strings.Trim(closing, "`") == "" {
+			i++
+			break
+		}
+		code = append(code, lines[i])
+	}
+
+	open := "<pre><code>"
+	if lang != "" {
+		open = `<pre><code class="language-` + html.EscapeString(lang) + `">`
+	}
+	text := ""
+	if len(code) > 0 {
+		text = strings.Join(code, "\n") + "\n"
+	}
+	return open + escapeText(text) + "</code></pre>", i
+}
+
+// isBlockquoteStart 内容のある「>」で始まる行かどうか
+func isBlockquoteStart(line string) bool {
+	loc := mdBlockquoteRegex.FindStringIndex(line)
+	return loc != nil && !isBlank(line[loc[1]:])
+}
+
+// renderBlockquote 「>」で始まる行が続く間を引用として変換（中身は再帰的にブロック要素として扱う）
+func renderBlockquote(lines []string, i int) (string, int) {
+	var inner []string
+	for ; i < len(lines); i++ {
+		loc := mdBlockquoteRegex.FindStringIndex(lines[i])
+		if loc == nil {
+			break
+		}
+		inner = append(inner, lines[i][loc[1]:])
+	}
+	return "<blockquote>\n" + renderBlocks(inner) + "\n</blockquote>", i
+}
+
+// renderList 同じ種類の項目が続く間を箇条書き・番号付きリストとして変換
+// 項目の後の字下げした行は項目の続きとして<br />でつなぐ
+func renderList(lines []string, i int) (string, int) {
+	ordered := isOrderedMarker(mdListItemRegex.FindStringSubmatch(lines[i])[1])
+
+	var items [][]string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if m := mdListItemRegex.FindStringSubmatch(line); m != nil {
+			if isOrderedMarker(m[1]) != ordered {
+				break
+			}
+			items = append(items, []string{strings.TrimSpace(m[2])})
+			continue
+		}
+		if isBlank(line) || indentWidth(line) < 2 {
+			break
+		}
+		last := len(items) - 1
+		items[last] = append(items[last], strings.TrimSpace(line))
+	}
+
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	var b strings.Builder
+	b.WriteString("<" + tag + ">\n")
+	for _, item := range items {
+		b.WriteString("<li>" + renderLines(item) + "</li>\n")
+	}
+	b.WriteString("</" + tag + ">")
+	return b.String(), i
+}
+
+// isOrderedMarker 番号付きリストの記号（「1.」など）かどうか
+func isOrderedMarker(marker string) bool {
+	return marker[0] >= '0' && marker[0] <= '9'
+}
+
+// isHTMLBlockStart 行頭のHTMLブロックの開始行かどうか
+func isHTMLBlockStart(line string) bool {
+	m := mdHTMLBlockRegex.FindStringSubmatch(line)
+	if m == nil {
+		return false
+	}
+	return m[1] == "" || htmlBlockTags[strings.ToLower(m[1])]
+}
+
+// renderHTMLBlock HTMLブロックを変換せずに出力（空行、または<pre>などの終了タグまで）
+func renderHTMLBlock(lines []string, i int) (string, int) {
+	m := mdHTMLBlockRegex.FindStringSubmatch(lines[i])
+	tag := strings.ToLower(m[1])
+
+	var end string
+	switch {
+	case m[1] == "":
+		end = "-->"
+	case htmlRawTags[tag]:
+		end = "</" + tag + ">"
+	}
+
+	var block []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if end == "" && isBlank(line) {
+			break
+		}
+		block = append(block, line)
+		if end != "" && strings.Contains(strings.ToLower(line), end) {
+			i++
+			break
+		}
+	}
+	return strings.Join(block, "\n"), i
+}
+
+// renderInline 強調・リンク・コードなどのインライン要素を変換
+func renderInline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch c {
+		case '\\':
+			if i+1 < len(s) && isASCIIPunct(s[i+1]) {
+				b.WriteString(escapeText(s[i+1 : i+2]))
+				i += 2
+				continue
+			}
+		case '`':
+			n := runLength(s, i, '`')
+			if end := strings.Index(s[i+n:], s[i:i+n]); end > 0 && runLength(s, i+n+end, '`') == n {
+				code := strings.TrimSpace(strings.ReplaceAll(s[i+n:i+n+end], "\n", " "))
+				b.WriteString("<code>" + escapeText(code) + "</code>")
+				i += n + end + n
+				continue
+			}
+			b.WriteString(s[i : i+n])
+			i += n
+			continue
+		case '<':
+			if tag := mdInlineTagRegex.FindString(s[i:]); tag != "" {
+				b.WriteString(tag)
+				i += len(tag)
+				continue
+			}
+			b.WriteString("&lt;")
+			i++
+			continue
+		case '>':
+			b.WriteString("&gt;")
+			i++
+			continue
+		case '&':
+			if entity := mdEntityRegex.FindString(s[i:]); entity != "" {
+				b.WriteString(entity)
+				i += len(entity)
+				continue
+			}
+			b.WriteString("&amp;")
+			i++
+			continue
+		case '!':
+			if text, dest, title, n, ok := parseLink(s[i+1:]); ok {
+				b.WriteString(`<img src="` + html.EscapeString(dest) + `" alt="` + html.EscapeString(text) + `"`)
+				if title != "" {
+					b.WriteString(` title="` + html.EscapeString(title) + `"`)
+				}
+				b.WriteString(" />")
+				i += 1 + n
+				continue
+			}
+		case '[':
+			if text, dest, title, n, ok := parseLink(s[i:]); ok {
+				b.WriteString(`<a href="` + html.EscapeString(dest) + `"`)
+				if title != "" {
+					b.WriteString(` title="` + html.EscapeString(title) + `"`)
+				}
+				b.WriteString(">" + renderInline(text) + "</a>")
+				i += n
+				continue
+			}
+		case '*':
+			n := runLength(s, i, '*')
+			if end, ok := findEmphasisCloser(s, i, n); ok {
+				tag := "em"
+				if n == 2 {
+					tag = "strong"
+				}
+				b.WriteString("<" + tag + ">" + renderInline(s[i+n:end]) + "</" + tag + ">")
+				i = end + n
+				continue
+			}
+			b.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+		b.WriteByte(c)
+		i++
+	}
+	return b.String()
+}
+
+// parseLink 「[テキスト](URL "タイトル")」形式のリンクを解析し、読み進めたバイト数を返す
+func parseLink(s string) (text, dest, title string, n int, ok bool) {
+	if s == "" || s[0] != '[' {
+		return "", "", "", 0, false
+	}
+
+	// 対応する「]」を探す（入れ子の角括弧とエスケープを考慮）
+	depth := 0
+	end := -1
+	for j := 0; j < len(s) && end < 0; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				end = j
+			}
+		}
+	}
+	if end < 0 || end+1 >= len(s) || s[end+1] != '(' {
+		return "", "", "", 0, false
+	}
+	text = s[1:end]
+
+	// URLは空白か対応する「)」まで（URL中の括弧の組は含める）
+	j := skipSpaces(s, end+2)
+	start, parens := j, 0
+	for ; j < len(s) && !isSpace(s[j]); j++ {
+		if s[j] == '(' {
+			parens++
+		} else if s[j] == ')' {
+			if parens == 0 {
+				break
+			}
+			parens--
+		}
+	}
+	dest = s[start:j]
+
+	j = skipSpaces(s, j)
+	if j < len(s) && s[j] == '"' {
+		close := strings.IndexByte(s[j+1:], '"')
+		if close < 0 {
+			return "", "", "", 0, false
+		}
+		title = s[j+1 : j+1+close]
+		j = skipSpaces(s, j+close+2)
+	}
+	if dest == "" || j >= len(s) || s[j] != ')' {
+		return "", "", "", 0, false
+	}
+	return text, dest, title, j + 1, true
+}
+
+// findEmphasisCloser 位置iから始まるn個の「*」（1つなら斜体、2つなら太字）に対応する終了記号の位置を探す
+// 開始記号の直後と終了記号の直前は空白以外の文字で、長さの異なる「*」の並びは入れ子として読み飛ばす
+func findEmphasisCloser(s string, i, n int) (int, bool) {
+	from := i + n
+	if n > 2 || from >= len(s) || isSpace(s[from]) {
+		return 0, false
+	}
+	for j := from; j < len(s); {
+		switch s[j] {
+		case '\\':
+			j += 2
+		case '`':
+			// コードスパンの中の記号は対象にしない
+			m := runLength(s, j, '`')
+			if end := strings.Index(s[j+m:], s[j:j+m]); end >= 0 {
+				j += m + end + m
+			} else {
+				j += m
+			}
+		case '*':
+			m := runLength(s, j, '*')
+			if m == n && j > from && !isSpace(s[j-1]) {
+				return j, true
+			}
+			j += m
+		default:
+			j++
+		}
+	}
+	return 0, false
+}
+
+// escapeText 本文のテキストをHTMLとしてエスケープ
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// indentWidth 行頭の空白の数
+func indentWidth(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func runLength(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && isSpace(s[i]) {
+		i++
+	}
+	return i
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
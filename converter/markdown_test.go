@@ -0,0 +1,147 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"mttohmd/entry"
+)
+
+func TestMarkdownToHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "段落と改行",
+			input:    "1行目\n2行目  \n3行目\\\n4行目\n\n次の段落",
+			expected: "<p>1行目<br />\n2行目<br />\n3行目<br />\n4行目</p>\n<p>次の段落</p>",
+		},
+		{
+			name:     "見出し",
+			input:    "# 見出し1\n## 見出し2 ##\n本文",
+			expected: "<h1>見出し1</h1>\n<h2>見出し2</h2>\n<p>本文</p>",
+		},
+		{
+			name:     "強調",
+			input:    "**太字** と *斜体* と snake_case_name",
+			expected: "<p><strong>太字</strong> と <em>斜体</em> と snake_case_name</p>",
+		},
+		{
+			name:     "入れ子の強調",
+			input:    "*a **b** c*",
+			expected: "<p><em>a <strong>b</strong> c</em></p>",
+		},
+		{
+			name:     "リンクと画像",
+			input:    `[リンク](https://example.com/?a=1&b=2 "タイトル") ![画像](https://example.com/a.png) [括弧](https://example.com/a_(b))`,
+			expected: `<p><a href="https://example.com/?a=1&amp;b=2" title="タイトル">リンク</a> <img src="https://example.com/a.png" alt="画像" /> <a href="https://example.com/a_(b)">括弧</a></p>`,
+		},
+		{
+			name:     "インラインコードとエスケープ",
+			input:    "`a < b && c` は 1 < 2 & \\*強調しない\\*",
+			expected: "<p><code>a &lt; b &amp;&amp; c</code> は 1 &lt; 2 &amp; *強調しない*</p>",
+		},
+		{
+			name:     "はてな記法とインラインのHTMLはそのまま",
+			input:    "[asin:B000000000:detail] と <span class=\"x\">span</span> &amp;",
+			expected: "<p>[asin:B000000000:detail] と <span class=\"x\">span</span> &amp;</p>",
+		},
+		{
+			name:     "コードブロック",
+			input:    "```go\nfmt.Println(\"<x>\")\n\n// 空行を含む\n```\n後",
+			expected: "<pre><code class=\"language-go\">fmt.Println(\"&lt;x&gt;\")\n\n// 空行を含む\n</code></pre>\n<p>後</p>",
+		},
+		{
+			name:     "引用",
+			input:    "> 引用1\n> 続き\n>\n> 引用2",
+			expected: "<blockquote>\n<p>引用1<br />\n続き</p>\n<p>引用2</p>\n</blockquote>",
+		},
+		{
+			name:     "リスト",
+			input:    "- a\n- b\n  続き\n- c\n1. one\n2. two",
+			expected: "<ul>\n<li>a</li>\n<li>b<br />\n続き</li>\n<li>c</li>\n</ul>\n<ol>\n<li>one</li>\n<li>two</li>\n</ol>",
+		},
+		{
+			name:     "段落の直後のリスト",
+			input:    "本文\n- 項目",
+			expected: "<p>本文</p>\n<ul>\n<li>項目</li>\n</ul>",
+		},
+		{
+			name:     "HTMLブロック",
+			input:    "<div class=\"box\">\n*そのまま*\n</div>\n\n<pre>\n\n**code**\n</pre>",
+			expected: "<div class=\"box\">\n*そのまま*\n</div>\n<pre>\n\n**code**\n</pre>",
+		},
+		{
+			name:     "対応しない記法は段落のテキスト",
+			input:    "| a | b |\n|---|---|\n\n***",
+			expected: "<p>| a | b |<br />\n|---|---|</p>\n<p>***</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := MarkdownToHTML(tt.input); result != tt.expected {
+				t.Errorf("MarkdownToHTML() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMarkdownToHTMLDegenerate(t *testing.T) {
+	// 記号だけの入力や閉じられていない記法は空の要素を作らず、テキストとして出力する
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "", expected: ""},
+		{input: " \n\n", expected: ""},
+		{input: "#", expected: "<p>#</p>"},
+		{input: "# ", expected: "<p>#</p>"},
+		{input: "*", expected: "<p>*</p>"},
+		{input: "-", expected: "<p>-</p>"},
+		{input: "- ", expected: "<p>-</p>"},
+		{input: "1.", expected: "<p>1.</p>"},
+		{input: ">", expected: "<p>&gt;</p>"},
+		{input: "**", expected: "<p>**</p>"},
+		{input: "**a*", expected: "<p>**a*</p>"},
+		{input: "* a*", expected: "<ul>\n<li>a*</li>\n</ul>"},
+		{input: "`", expected: "<p>`</p>"},
+		{input: "``", expected: "<p>``</p>"},
+		{input: "[", expected: "<p>[</p>"},
+		{input: "![", expected: "<p>![</p>"},
+		{input: "[a](", expected: "<p>[a](</p>"},
+		{input: "[a]()", expected: "<p>[a]()</p>"},
+		{input: "<", expected: "<p>&lt;</p>"},
+		{input: "&", expected: "<p>&amp;</p>"},
+		{input: "\\", expected: "<p>\\</p>"},
+		{input: "```", expected: "<pre><code></code></pre>"},
+		{input: "```\ncode", expected: "<pre><code>code\n</code></pre>"},
+	}
+
+	for _, tt := range tests {
+		if result := MarkdownToHTML(tt.input); result != tt.expected {
+			t.Errorf("MarkdownToHTML(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestMarkdownToHTMLFromToMarkdown(t *testing.T) {
+	// ToMarkdownで変換した本文をHTMLに戻すと同じ構造になる
+	e := entry.Entry{
+		Title: "往復",
+		Body: "<p>本文の<strong>太字</strong>と<em>斜体</em>と<code>a &lt; b</code><br>2行目</p>\n" +
+			"<h2>見出し</h2>\n<ul>\n<li>項目1</li>\n<li><a href=\"https://example.com/\">リンク</a></li>\n</ul>\n" +
+			"<blockquote>引用</blockquote>\n<p><img src=\"https://example.com/a.png\" alt=\"画像\"></p>",
+	}
+
+	md := ToMarkdown(e)
+	_, body, _ := strings.Cut(md, "---\n\n")
+	expected := "<p>本文の<strong>太字</strong>と<em>斜体</em>と<code>a &lt; b</code><br />\n2行目</p>\n" +
+		"<h2>見出し</h2>\n<ul>\n<li>項目1</li>\n<li><a href=\"https://example.com/\">リンク</a></li>\n</ul>\n" +
+		"<blockquote>\n<p>引用</p>\n</blockquote>\n<p><img src=\"https://example.com/a.png\" alt=\"画像\" /></p>"
+	if result := MarkdownToHTML(body); result != expected {
+		t.Errorf("MarkdownToHTML() = %q, want %q\nMarkdown: %q", result, expected, body)
+	}
+}
@@ -0,0 +1,223 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"mttohmd/entry"
+)
+
+// frontMatterDelimiter フロントマターの開始・終了行
+const frontMatterDelimiter = "---"
+
+// frontMatterDateLayouts フロントマターのDateとして受け付ける日時の形式（ToMarkdownの出力はRFC3339）
+var frontMatterDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// FromMarkdown はてなブログ形式のMarkdown（ToMarkdownの出力）をエントリーに変換
+// フロントマターのTitle・Category・Date・Excerptと、はてなブログの同期ツールが使う
+// CustomPath（BASENAME）・Draft（STATUS）を読み取り、本文は「続きを読む」記法で本文と追記に分けてHTMLに変換する
+// 変換後の本文はHTMLなのでCONVERT BREAKSは0（改行を変換しない）になる
+func FromMarkdown(md string) (entry.Entry, error) {
+	e, body, err := splitMarkdown(md)
+	if err != nil {
+		return entry.Entry{}, err
+	}
+
+	extended := ""
+	if before, after, ok := cutMoreMarker(body); ok {
+		body, extended = before, after
+	}
+	e.Body = MarkdownToHTML(strings.TrimSpace(body))
+	if strings.TrimSpace(extended) != "" {
+		e.ExtendedBody = MarkdownToHTML(strings.TrimSpace(extended))
+	}
+	return e, nil
+}
+
+// ParseFrontMatter はてなブログ形式のMarkdownのフロントマターだけをエントリーに変換
+// 読み取る項目はFromMarkdownと同じで、本文は変換しない（BodyとExtendedBodyは空のまま）
+func ParseFrontMatter(md string) (entry.Entry, error) {
+	e, _, err := splitMarkdown(md)
+	return e, err
+}
+
+// splitMarkdown フロントマターを読み取ったエントリーと、フロントマターの後の本文を返す
+func splitMarkdown(md string) (entry.Entry, string, error) {
+	md = strings.ReplaceAll(md, "\r\n", "\n")
+	md = strings.TrimPrefix(md, "\ufeff")
+
+	lines := strings.Split(md, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != frontMatterDelimiter {
+		return entry.Entry{}, "", errors.New("フロントマターがありません")
+	}
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == frontMatterDelimiter {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return entry.Entry{}, "", errors.New("フロントマターが --- で閉じられていません")
+	}
+
+	e := entry.Entry{
+		Status:        entry.StatusPublish,
+		ConvertBreaks: entry.ConvertBreaksNone,
+	}
+	if err := parseFrontMatter(&e, lines[1:end]); err != nil {
+		return entry.Entry{}, "", err
+	}
+	if e.Title == "" {
+		return entry.Entry{}, "", errors.New("フロントマターに Title がありません")
+	}
+	return e, strings.Join(lines[end+1:], "\n"), nil
+}
+
+// parseFrontMatter フロントマターの「キー: 値」とカテゴリーの一覧を読み取る
+func parseFrontMatter(e *entry.Entry, lines []string) error {
+	listKey := ""
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		// 「- 値」はCategoryなど直前のキーの一覧
+		if item, ok := strings.CutPrefix(strings.TrimSpace(line), "- "); ok && listKey != "" {
+			if listKey == "Category" {
+				name, err := unquoteYAML(strings.TrimSpace(item))
+				if err != nil {
+					return err
+				}
+				e.Categories = append(e.Categories, entry.Category{Name: name})
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return fmt.Errorf("フロントマターの行を解釈できません: %q", line)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		listKey = ""
+
+		// 「[a, b]」形式の一覧は要素ごとに引用符を外す
+		if key == "Category" && strings.HasPrefix(value, "[") {
+			names, err := splitInlineList(value)
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				e.Categories = append(e.Categories, entry.Category{Name: name})
+			}
+			continue
+		}
+		value, err := unquoteYAML(value)
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case "Title":
+			e.Title = value
+		case "Category":
+			if value == "" {
+				listKey = key
+			} else {
+				e.Categories = append(e.Categories, entry.Category{Name: value})
+			}
+		case "Date":
+			t, err := parseFrontMatterDate(value)
+			if err != nil {
+				return err
+			}
+			e.DateTime = t
+			e.Date = entry.FormatDate(t)
+		case "Excerpt":
+			e.Excerpt = value
+		case "CustomPath":
+			e.Basename = value
+		case "Draft":
+			if value == "true" || value == "yes" {
+				e.Status = entry.StatusDraft
+			}
+		default:
+			if value == "" {
+				listKey = key
+			}
+		}
+	}
+	return nil
+}
+
+// parseFrontMatterDate フロントマターのDateを解釈（MT形式の日付も受け付ける）
+func parseFrontMatterDate(value string) (time.Time, error) {
+	for _, layout := range frontMatterDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return entry.ParseDate(value, nil)
+}
+
+// cutMoreMarker 「続きを読む」記法だけの行で本文を前後に分ける
+func cutMoreMarker(body string) (before, after string, found bool) {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == moreMarker {
+			return strings.Join(lines[:i], "\n"), strings.Join(lines[i+1:], "\n"), true
+		}
+	}
+	return body, "", false
+}
+
+// splitInlineList 「[a, b]」形式の一覧を分割（引用符の中の「,」では分割しない）
+func splitInlineList(value string) ([]string, error) {
+	inner, ok := strings.CutSuffix(strings.TrimPrefix(value, "["), "]")
+	if !ok {
+		return nil, fmt.Errorf("一覧が ] で閉じられていません: %s", value)
+	}
+
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i <= len(inner); i++ {
+		if i < len(inner) {
+			c := inner[i]
+			switch {
+			case quote == '"' && c == '\\':
+				i++
+				continue
+			case quote != 0:
+				if c == quote {
+					quote = 0
+				}
+				continue
+			case c == '"' || c == '\'':
+				quote = c
+				continue
+			case c != ',':
+				continue
+			}
+		}
+
+		item, err := unquoteYAML(strings.TrimSpace(inner[start:i]))
+		if err != nil {
+			return nil, err
+		}
+		if item != "" {
+			items = append(items, item)
+		}
+		start = i + 1
+	}
+	return items, nil
+}
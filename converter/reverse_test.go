@@ -0,0 +1,248 @@
+package converter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"mttohmd/entry"
+)
+
+func TestFromMarkdown(t *testing.T) {
+	input := `---
+Title: テスト: 記事
+Category:
+- Go
+- "日記"
+Date: 2023-01-15T15:00:00+09:00
+Excerpt: 概要です
+CustomPath: 2023/01/15/150000
+Slug: tesuto
+---
+
+本文の**1行目**
+2行目
+
+<!-- more -->
+
+- 追記
+`
+
+	e, err := FromMarkdown(input)
+	if err != nil {
+		t.Fatalf("FromMarkdown failed: %v", err)
+	}
+
+	if e.Title != "テスト: 記事" {
+		t.Errorf("Expected title 'テスト: 記事', got %q", e.Title)
+	}
+	expectedCategories := []entry.Category{{Name: "Go"}, {Name: "日記"}}
+	if !reflect.DeepEqual(e.Categories, expectedCategories) {
+		t.Errorf("Categories = %v, want %v", e.Categories, expectedCategories)
+	}
+	if !e.DateTime.Equal(time.Date(2023, 1, 15, 6, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected date 2023-01-15T06:00:00Z, got %v", e.DateTime)
+	}
+	if e.Date != "01/15/2023 03:00:00 PM" {
+		t.Errorf("Expected MT date '01/15/2023 03:00:00 PM', got %q", e.Date)
+	}
+	if e.Excerpt != "概要です" || e.Basename != "2023/01/15/150000" {
+		t.Errorf("Expected excerpt and basename, got %q and %q", e.Excerpt, e.Basename)
+	}
+	if e.Status != entry.StatusPublish || e.ConvertBreaks != entry.ConvertBreaksNone {
+		t.Errorf("Expected Publish and CONVERT BREAKS 0, got %q and %q", e.Status, e.ConvertBreaks)
+	}
+	if e.Body != "<p>本文の<strong>1行目</strong><br />\n2行目</p>" {
+		t.Errorf("Unexpected body: %q", e.Body)
+	}
+	if e.ExtendedBody != "<ul>\n<li>追記</li>\n</ul>" {
+		t.Errorf("Unexpected extended body: %q", e.ExtendedBody)
+	}
+}
+
+func TestFromMarkdownFrontMatterVariants(t *testing.T) {
+	e, err := FromMarkdown("---\r\nTitle: '引用符'\r\nCategory: [Go, \"テスト\"]\r\nDate: 01/15/2023 03:00:00 PM\r\nDraft: true\r\n---\r\n")
+	if err != nil {
+		t.Fatalf("FromMarkdown failed: %v", err)
+	}
+
+	if e.Title != "引用符" {
+		t.Errorf("Expected title '引用符', got %q", e.Title)
+	}
+	if names := e.CategoryNames(); !reflect.DeepEqual(names, []string{"Go", "テスト"}) {
+		t.Errorf("Expected categories [Go テスト], got %v", names)
+	}
+	if e.DateTime.IsZero() || e.Date != "01/15/2023 03:00:00 PM" {
+		t.Errorf("Expected MT-style date to be parsed, got %q", e.Date)
+	}
+	if e.Status != entry.StatusDraft {
+		t.Errorf("Expected Draft status, got %q", e.Status)
+	}
+	if e.Body != "" || e.ExtendedBody != "" {
+		t.Errorf("Expected empty body, got %q and %q", e.Body, e.ExtendedBody)
+	}
+}
+
+func TestParseFrontMatter(t *testing.T) {
+	e, err := ParseFrontMatter("---\nTitle: タイトル\nCategory:\n- Go\nDate: 2023-01-15T15:00:00+09:00\n---\n\n**本文**\n")
+	if err != nil {
+		t.Fatalf("ParseFrontMatter failed: %v", err)
+	}
+	if e.Title != "タイトル" || e.Date != "01/15/2023 03:00:00 PM" {
+		t.Errorf("Unexpected metadata: %q, %q", e.Title, e.Date)
+	}
+	if names := e.CategoryNames(); !reflect.DeepEqual(names, []string{"Go"}) {
+		t.Errorf("Expected categories [Go], got %v", names)
+	}
+	if e.Body != "" || e.ExtendedBody != "" {
+		t.Errorf("Expected body not to be converted, got %q and %q", e.Body, e.ExtendedBody)
+	}
+
+	if _, err := ParseFrontMatter("---\nCategory: Go\n---\n"); err == nil {
+		t.Error("Expected error for missing Title, got nil")
+	}
+}
+
+func TestFromMarkdownErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "フロントマターなし", input: "本文だけ"},
+		{name: "閉じられていないフロントマター", input: "---\nTitle: x\n本文"},
+		{name: "Titleなし", input: "---\nCategory: Go\n---\n本文"},
+		{name: "解釈できない日付", input: "---\nTitle: x\nDate: 昨日\n---\n"},
+		{name: "解釈できない行", input: "---\nTitle: x\nおかしな行\n---\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FromMarkdown(tt.input); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestFromMarkdownRoundTrip(t *testing.T) {
+	// ToMarkdownの出力を読み戻すとメタデータが復元される
+	original := entry.Entry{
+		Title:        "往復テスト",
+		Date:         "01/15/2023 03:00:00 PM",
+		DateTime:     time.Date(2023, 1, 15, 15, 0, 0, 0, time.FixedZone("JST", 9*60*60)),
+		Categories:   []entry.Category{{Name: "Go"}, {Name: "テスト"}},
+		Excerpt:      "概要",
+		Body:         "<p>本文</p>",
+		ExtendedBody: "<p>追記</p>",
+	}
+
+	e, err := FromMarkdown(ToMarkdown(original))
+	if err != nil {
+		t.Fatalf("FromMarkdown failed: %v", err)
+	}
+
+	if e.Title != original.Title || e.Excerpt != original.Excerpt || e.Date != original.Date {
+		t.Errorf("Metadata mismatch: got %+v", e)
+	}
+	if !reflect.DeepEqual(e.Categories, original.Categories) || !e.DateTime.Equal(original.DateTime) {
+		t.Errorf("Categories or date mismatch: got %v, %v", e.Categories, e.DateTime)
+	}
+	if !strings.Contains(e.Body, "本文") || !strings.Contains(e.ExtendedBody, "追記") {
+		t.Errorf("Body mismatch: got %q and %q", e.Body, e.ExtendedBody)
+	}
+
+	// YAMLで特別な意味を持つ値も引用符を付けて出力し、そのまま読み戻せる
+	values := []string{
+		`"quoted"`,
+		`'single'`,
+		`it's`,
+		`say "hi"`,
+		`back\slash`,
+		`- list`,
+		`[tag]`,
+		`#hash`,
+		`key: value`,
+		`末尾:`,
+		` 前後の空白 `,
+		`true`,
+		`2023`,
+		`a, b`,
+		`日本語`,
+	}
+
+	for _, value := range values {
+		t.Run(value, func(t *testing.T) {
+			original := entry.Entry{
+				Title:      value,
+				Categories: []entry.Category{{Name: value}, {Name: "Go"}},
+				Excerpt:    value,
+			}
+
+			md := ToMarkdown(original)
+			e, err := FromMarkdown(md)
+			if err != nil {
+				t.Fatalf("FromMarkdown failed: %v\n%s", err, md)
+			}
+			if e.Title != value {
+				t.Errorf("Title = %q, want %q", e.Title, value)
+			}
+			if !reflect.DeepEqual(e.Categories, original.Categories) {
+				t.Errorf("Categories = %v, want %v", e.Categories, original.Categories)
+			}
+			if e.Excerpt != strings.TrimSpace(value) {
+				t.Errorf("Excerpt = %q, want %q", e.Excerpt, strings.TrimSpace(value))
+			}
+		})
+	}
+}
+
+func TestFromMarkdownRoundTripStatusAndBasename(t *testing.T) {
+	// 下書きとBASENAMEはconvert→reverseで失われない（公開済みのエントリーを下書きにしない）
+	tests := []struct {
+		name  string
+		entry entry.Entry
+	}{
+		{name: "下書き", entry: entry.Entry{Title: "下書き", Status: entry.StatusDraft, Basename: "2023/01/15/150000", Body: "<p>本文</p>"}},
+		{name: "公開", entry: entry.Entry{Title: "公開", Status: entry.StatusPublish, Basename: "entry/custom-path", Body: "<p>本文</p>"}},
+		{name: "BASENAMEなし", entry: entry.Entry{Title: "なし", Status: entry.StatusPublish, Body: "<p>本文</p>"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := ToMarkdown(tt.entry)
+			e, err := FromMarkdown(md)
+			if err != nil {
+				t.Fatalf("FromMarkdown failed: %v\n%s", err, md)
+			}
+			if e.Status != tt.entry.Status {
+				t.Errorf("Status = %q, want %q\n%s", e.Status, tt.entry.Status, md)
+			}
+			if e.Basename != tt.entry.Basename {
+				t.Errorf("Basename = %q, want %q\n%s", e.Basename, tt.entry.Basename, md)
+			}
+		})
+	}
+}
+
+func TestFromMarkdownQuotedValues(t *testing.T) {
+	e, err := FromMarkdown("---\nTitle: 'it''s'\nCategory: [\"a, b\", 'c', d]\n---\n")
+	if err != nil {
+		t.Fatalf("FromMarkdown failed: %v", err)
+	}
+	if e.Title != "it's" {
+		t.Errorf("Expected title \"it's\", got %q", e.Title)
+	}
+	if names := e.CategoryNames(); !reflect.DeepEqual(names, []string{"a, b", "c", "d"}) {
+		t.Errorf("Expected categories [a, b c d], got %q", names)
+	}
+
+	for _, input := range []string{
+		"---\nTitle: \"閉じていない\n---\n",
+		"---\nTitle: 'x'y'\n---\n",
+	} {
+		if _, err := FromMarkdown(input); err == nil {
+			t.Errorf("FromMarkdown(%q) expected error, got nil", input)
+		}
+	}
+}
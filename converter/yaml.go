@@ -0,0 +1,77 @@
+package converter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// yamlPlainIndicators プレーンスカラーの先頭に置けないYAMLの記号
+const yamlPlainIndicators = "-?:,[]{}#&*!|>'\"%@`"
+
+// yamlReserved YAMLで文字列以外（真偽値・null）として解釈される値
+var yamlReserved = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"null": true, "~": true,
+}
+
+// quoteYAML フロントマターの値をYAMLのスカラーとして出力できる形にする
+// そのまま書いても同じ文字列として読める値は引用符を付けず、それ以外はダブルクォートで囲んでエスケープする
+func quoteYAML(value string) string {
+	if isPlainYAML(value) {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+// isPlainYAML 引用符なしで書いても同じ文字列として読み戻せるかどうか
+func isPlainYAML(value string) bool {
+	if value == "" || value != strings.TrimSpace(value) {
+		return false
+	}
+	if strings.ContainsRune(yamlPlainIndicators, rune(value[0])) {
+		return false
+	}
+	if strings.Contains(value, ": ") || strings.Contains(value, " #") || strings.HasSuffix(value, ":") {
+		return false
+	}
+	if yamlReserved[strings.ToLower(value)] {
+		return false
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return false
+	}
+	for _, r := range value {
+		if r < 0x20 || r == 0x7f || r == utf8.RuneError {
+			return false
+		}
+	}
+	return true
+}
+
+// unquoteYAML YAMLのスカラーを文字列に戻す
+// ダブルクォートはエスケープを解釈し、シングルクォートは2つ続いた引用符を1つに戻す。引用符のない値はそのまま返す
+func unquoteYAML(value string) (string, error) {
+	if value == "" {
+		return value, nil
+	}
+	switch value[0] {
+	case '"':
+		s, err := strconv.Unquote(value)
+		if err != nil {
+			return "", fmt.Errorf("ダブルクォートで囲まれた値を解釈できません: %s", value)
+		}
+		return s, nil
+	case '\'':
+		if len(value) < 2 || value[len(value)-1] != '\'' {
+			return "", fmt.Errorf("シングルクォートで囲まれた値を解釈できません: %s", value)
+		}
+		inner := value[1 : len(value)-1]
+		if strings.Contains(strings.ReplaceAll(inner, "''", ""), "'") {
+			return "", fmt.Errorf("シングルクォートで囲まれた値を解釈できません: %s", value)
+		}
+		return strings.ReplaceAll(inner, "''", "'"), nil
+	}
+	return value, nil
+}
@@ -0,0 +1,52 @@
+package entry
+
+// Category エントリーのカテゴリーを表現する構造体
+type Category struct {
+	Name    string
+	Primary bool
+}
+
+// addCategory CATEGORY行のカテゴリーを追加（同名のカテゴリーは重複させない）
+func (e *Entry) addCategory(name string) {
+	for _, c := range e.Categories {
+		if c.Name == name {
+			return
+		}
+	}
+	e.Categories = append(e.Categories, Category{Name: name})
+}
+
+// setPrimaryCategory PRIMARY CATEGORY行のカテゴリーを主カテゴリーとして設定
+func (e *Entry) setPrimaryCategory(name string) {
+	for i := range e.Categories {
+		e.Categories[i].Primary = e.Categories[i].Name == name
+	}
+	for _, c := range e.Categories {
+		if c.Primary {
+			return
+		}
+	}
+	e.Categories = append(e.Categories, Category{Name: name, Primary: true})
+}
+
+// PrimaryCategory 主カテゴリー名を返す（未指定の場合は最初のカテゴリー、カテゴリーがなければ空）
+func (e Entry) PrimaryCategory() string {
+	for _, c := range e.Categories {
+		if c.Primary {
+			return c.Name
+		}
+	}
+	if len(e.Categories) > 0 {
+		return e.Categories[0].Name
+	}
+	return ""
+}
+
+// CategoryNames カテゴリー名の一覧を返す
+func (e Entry) CategoryNames() []string {
+	names := make([]string, 0, len(e.Categories))
+	for _, c := range e.Categories {
+		names = append(names, c.Name)
+	}
+	return names
+}
@@ -0,0 +1,83 @@
+package entry
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetPrimaryCategory(t *testing.T) {
+	var e Entry
+	e.addCategory("Go")
+	e.addCategory("テスト")
+	e.addCategory("Go")
+
+	// 既存のカテゴリーを主カテゴリーにする
+	e.setPrimaryCategory("テスト")
+	expected := []Category{{Name: "Go"}, {Name: "テスト", Primary: true}}
+	if !reflect.DeepEqual(e.Categories, expected) {
+		t.Errorf("Categories = %v, want %v", e.Categories, expected)
+	}
+
+	// 未登録のカテゴリーは主カテゴリーとして追加される
+	e.setPrimaryCategory("新規")
+	expected = []Category{{Name: "Go"}, {Name: "テスト"}, {Name: "新規", Primary: true}}
+	if !reflect.DeepEqual(e.Categories, expected) {
+		t.Errorf("Categories = %v, want %v", e.Categories, expected)
+	}
+}
+
+func TestPrimaryCategory(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories []Category
+		expected   string
+	}{
+		{
+			name:       "カテゴリーなし",
+			categories: nil,
+			expected:   "",
+		},
+		{
+			name:       "主カテゴリー指定なし",
+			categories: []Category{{Name: "Go"}, {Name: "テスト"}},
+			expected:   "Go",
+		},
+		{
+			name:       "主カテゴリー指定あり",
+			categories: []Category{{Name: "Go"}, {Name: "テスト", Primary: true}},
+			expected:   "テスト",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Entry{Categories: tt.categories}
+			if result := e.PrimaryCategory(); result != tt.expected {
+				t.Errorf("PrimaryCategory() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCategoryNames(t *testing.T) {
+	e := Entry{Categories: []Category{{Name: "Go", Primary: true}, {Name: "テスト"}}}
+	expected := []string{"Go", "テスト"}
+	if result := e.CategoryNames(); !reflect.DeepEqual(result, expected) {
+		t.Errorf("CategoryNames() = %v, want %v", result, expected)
+	}
+}
+
+func TestParserPrimaryCategoryKeepsOrder(t *testing.T) {
+	// PRIMARY CATEGORYが先に現れてもCATEGORY行の順序を保つ
+	input := "TITLE: Test\nCATEGORY: A\nPRIMARY CATEGORY: B\nCATEGORY: B\n-----\nBODY:\n本文\n-----\n--------\n"
+	entries, err := ParseReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseReader failed: %v", err)
+	}
+
+	expected := []Category{{Name: "A"}, {Name: "B", Primary: true}}
+	if len(entries) != 1 || !reflect.DeepEqual(entries[0].Categories, expected) {
+		t.Errorf("Categories = %v, want %v", entries, expected)
+	}
+}
@@ -0,0 +1,54 @@
+package entry
+
+import "strings"
+
+// Comment エントリーに付いたコメントを表現する構造体
+type Comment struct {
+	Author string
+	Email  string
+	IP     string
+	URL    string
+	Date   string
+	Body   string
+}
+
+// parseComment COMMENTセクションの行からコメントを組み立てる
+func parseComment(lines []string) Comment {
+	var c Comment
+	c.Body = parseMetaLines(lines, c.metaField)
+	return c
+}
+
+// metaField メタデータのキーに対応するフィールドを返す（未知のキーはnil）
+func (c *Comment) metaField(key string) *string {
+	switch key {
+	case "AUTHOR":
+		return &c.Author
+	case "EMAIL":
+		return &c.Email
+	case "IP":
+		return &c.IP
+	case "URL":
+		return &c.URL
+	case "DATE":
+		return &c.Date
+	}
+	return nil
+}
+
+// parseMetaLines 先頭に並ぶメタデータ行をフィールドに読み取り、残りの行を本文として返す
+func parseMetaLines(lines []string, metaField func(key string) *string) string {
+	i := 0
+	for ; i < len(lines); i++ {
+		key, value, ok := strings.Cut(lines[i], ":")
+		if !ok {
+			break
+		}
+		field := metaField(key)
+		if field == nil {
+			break
+		}
+		*field = strings.TrimPrefix(value, " ")
+	}
+	return strings.Join(lines[i:], "\n")
+}
@@ -0,0 +1,33 @@
+package entry
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateLayout MT形式でDATEを出力する際のレイアウト
+const DateLayout = "01/02/2006 03:04:05 PM"
+
+// dateLayouts MT形式のDATEとして受け付けるレイアウト（12時間表記と24時間表記）
+var dateLayouts = []string{
+	"1/2/2006 3:04:05 PM",
+	"1/2/2006 15:04:05",
+}
+
+// ParseDate MT形式の日付文字列をlocのタイムゾーンの時刻として解析（locがnilの場合はローカルタイム）
+func ParseDate(s string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("日付を解釈できません: %q", s)
+}
+
+// FormatDate 時刻をMT形式の日付文字列に変換
+func FormatDate(t time.Time) string {
+	return t.Format(DateLayout)
+}
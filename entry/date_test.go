@@ -0,0 +1,69 @@
+package entry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Time
+	}{
+		{
+			name:     "12時間表記（午前0時）",
+			input:    "01/15/2023 12:00:00 AM",
+			expected: time.Date(2023, 1, 15, 0, 0, 0, 0, jst),
+		},
+		{
+			name:     "12時間表記（午後）",
+			input:    "01/15/2023 03:04:05 PM",
+			expected: time.Date(2023, 1, 15, 15, 4, 5, 0, jst),
+		},
+		{
+			name:     "12時間表記（正午）",
+			input:    "01/15/2023 12:30:00 PM",
+			expected: time.Date(2023, 1, 15, 12, 30, 0, 0, jst),
+		},
+		{
+			name:     "24時間表記",
+			input:    "01/15/2023 15:04:05",
+			expected: time.Date(2023, 1, 15, 15, 4, 5, 0, jst),
+		},
+		{
+			name:     "1桁の月日と時",
+			input:    "1/5/2023 9:04:05 AM",
+			expected: time.Date(2023, 1, 5, 9, 4, 5, 0, jst),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDate(tt.input, jst)
+			if err != nil {
+				t.Fatalf("ParseDate() failed: %v", err)
+			}
+			if !result.Equal(tt.expected) || result.Location() != jst {
+				t.Errorf("ParseDate() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseDateInvalid(t *testing.T) {
+	for _, input := range []string{"", "2023-01-15", "13/01/2023 10:00:00", "01/15/2023 14:30:45 PM"} {
+		if _, err := ParseDate(input, time.UTC); err == nil {
+			t.Errorf("ParseDate(%q) expected error, got nil", input)
+		}
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	result := FormatDate(time.Date(2023, 1, 15, 15, 4, 5, 0, time.UTC))
+	if result != "01/15/2023 03:04:05 PM" {
+		t.Errorf("FormatDate() = %q, want %q", result, "01/15/2023 03:04:05 PM")
+	}
+}
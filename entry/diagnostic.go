@@ -0,0 +1,25 @@
+package entry
+
+import "fmt"
+
+// Diagnostic 解析中に見つかった問題（ファイル名・行番号・エントリー番号付き）
+type Diagnostic struct {
+	File    string
+	Line    int
+	Entry   int
+	Message string
+}
+
+// String 「ファイル:行: エントリーN: メッセージ」形式の文字列を返す
+func (d Diagnostic) String() string {
+	file := d.File
+	if file == "" {
+		file = "<input>"
+	}
+	return fmt.Sprintf("%s:%d: エントリー%d: %s", file, d.Line, d.Entry, d.Message)
+}
+
+// Error strictモードでエラーとして返すためのerror実装
+func (d *Diagnostic) Error() string {
+	return d.String()
+}
@@ -0,0 +1,297 @@
+package entry
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Encoding 入力ファイルの文字コード
+type Encoding int
+
+const (
+	EncodingAuto Encoding = iota
+	EncodingUTF8
+	EncodingShiftJIS
+	EncodingEUCJP
+)
+
+//go:generate go run gen_jis_table.go
+
+// jisRows jisTableの区の数（JIS X 0208の94区とCP932の拡張領域）
+const jisRows = 120
+
+// jisX0208Overrides CP932（Microsoftの対応）とJIS X 0208で対応するUnicodeが異なる区点
+// 添字はjisTableと同じ。EUC-JPはJIS X 0208の対応で変換する
+var jisX0208Overrides = map[int]rune{
+	0*94 + 32: 0x301C, // 1区33点 WAVE DASH（CP932はU+FF5E FULLWIDTH TILDE）
+	0*94 + 33: 0x2016, // 1区34点 DOUBLE VERTICAL LINE（CP932はU+2225 PARALLEL TO）
+	0*94 + 60: 0x2212, // 1区61点 MINUS SIGN（CP932はU+FF0D FULLWIDTH HYPHEN-MINUS）
+	0*94 + 80: 0x00A2, // 1区81点 CENT SIGN（CP932はU+FFE0 FULLWIDTH CENT SIGN）
+	0*94 + 81: 0x00A3, // 1区82点 POUND SIGN（CP932はU+FFE1 FULLWIDTH POUND SIGN）
+	1*94 + 43: 0x00AC, // 2区44点 NOT SIGN（CP932はU+FFE2 FULLWIDTH NOT SIGN）
+}
+
+// detectSampleSize 文字コードの自動判定に使う先頭のバイト数
+const detectSampleSize = 64 * 1024
+
+// utf8BOM UTF-8のバイトオーダーマーク
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// String 文字コード名を返す
+func (enc Encoding) String() string {
+	switch enc {
+	case EncodingAuto:
+		return "auto"
+	case EncodingUTF8:
+		return "UTF-8"
+	case EncodingShiftJIS:
+		return "Shift_JIS"
+	case EncodingEUCJP:
+		return "EUC-JP"
+	}
+	return fmt.Sprintf("Encoding(%d)", int(enc))
+}
+
+// ParseEncoding 文字コード名からEncodingを返す（大文字小文字と-/_の違いは無視）
+func ParseEncoding(name string) (Encoding, error) {
+	switch strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name)) {
+	case "", "auto":
+		return EncodingAuto, nil
+	case "utf8":
+		return EncodingUTF8, nil
+	case "shiftjis", "sjis", "cp932", "windows31j":
+		return EncodingShiftJIS, nil
+	case "eucjp":
+		return EncodingEUCJP, nil
+	}
+	return EncodingAuto, fmt.Errorf("未対応の文字コードです: %s", name)
+}
+
+// DetectEncoding BOMとバイト列の特徴から文字コードを推定
+// UTF-8として正しければUTF-8、そうでなければShift_JISとEUC-JPのうち不正なバイト列が少なく、
+// かなとして解釈できる文字が多い方を選ぶ
+func DetectEncoding(data []byte) Encoding {
+	if bytes.HasPrefix(data, utf8BOM) || utf8.Valid(trimIncompleteRune(data)) {
+		return EncodingUTF8
+	}
+
+	sjisErrors, sjisKana := scoreEncoding(data, decodeShiftJIS)
+	eucErrors, eucKana := scoreEncoding(data, decodeEUCJP)
+	if sjisErrors != eucErrors {
+		if sjisErrors < eucErrors {
+			return EncodingShiftJIS
+		}
+		return EncodingEUCJP
+	}
+	if eucKana > sjisKana {
+		return EncodingEUCJP
+	}
+	return EncodingShiftJIS
+}
+
+// NewDecoder 指定した文字コードのio.ReaderをUTF-8として読み出すio.Readerを返す
+// EncodingAutoの場合は先頭を読んで文字コードを判定する。UTF-8のBOMは取り除く
+func NewDecoder(r io.Reader, enc Encoding) (io.Reader, Encoding, error) {
+	br := bufio.NewReaderSize(r, detectSampleSize)
+	if enc == EncodingAuto {
+		sample, err := br.Peek(detectSampleSize)
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			return nil, enc, err
+		}
+		enc = DetectEncoding(sample)
+	}
+
+	switch enc {
+	case EncodingUTF8:
+		if head, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(head, utf8BOM) {
+			br.Discard(len(utf8BOM))
+		}
+		return br, enc, nil
+	case EncodingShiftJIS:
+		return &decoder{src: br, decode: decodeShiftJIS}, enc, nil
+	case EncodingEUCJP:
+		return &decoder{src: br, decode: decodeEUCJP}, enc, nil
+	}
+	return nil, enc, fmt.Errorf("未対応の文字コードです: %v", enc)
+}
+
+// byteSource 1バイトずつ読み出し、1バイトだけ戻せる入力
+type byteSource interface {
+	io.ByteReader
+	UnreadByte() error
+}
+
+// decoder マルチバイト文字コードを1文字ずつUTF-8に変換するio.Reader
+type decoder struct {
+	src    byteSource
+	decode func(byteSource) (rune, error)
+	buf    []byte
+}
+
+// Read 変換済みのUTF-8バイト列を読み出す
+func (d *decoder) Read(p []byte) (int, error) {
+	for len(d.buf) < len(p) {
+		r, err := d.decode(d.src)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		d.buf = utf8.AppendRune(d.buf, r)
+	}
+	if len(d.buf) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+// decodeShiftJIS Shift_JIS（CP932）の1文字を読み出す（不正なバイト列はU+FFFD）
+func decodeShiftJIS(src byteSource) (rune, error) {
+	b, err := src.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case b < 0x80:
+		return rune(b), nil
+	case 0xA1 <= b && b <= 0xDF:
+		// 半角カタカナ
+		return 0xFF61 + rune(b-0xA1), nil
+	case !(0x81 <= b && b <= 0x9F || 0xE0 <= b && b <= 0xFC):
+		return utf8.RuneError, nil
+	}
+
+	t, err := src.ReadByte()
+	if err == io.EOF {
+		return utf8.RuneError, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !(0x40 <= t && t <= 0x7E || 0x80 <= t && t <= 0xFC) {
+		// 2バイト目として不正なバイトは次の文字として読み直す
+		src.UnreadByte()
+		return utf8.RuneError, nil
+	}
+
+	row := int(b) - 0x81
+	if b >= 0xE0 {
+		row = int(b) - 0xC1
+	}
+	row *= 2
+	var cell int
+	if t >= 0x9F {
+		row++
+		cell = int(t) - 0x9F
+	} else {
+		cell = int(t) - 0x40
+		if t > 0x7F {
+			cell--
+		}
+	}
+	return lookupJIS(row, cell), nil
+}
+
+// decodeEUCJP EUC-JPの1文字を読み出す（JIS X 0212の補助漢字と不正なバイト列はU+FFFD）
+// 2バイト文字はJIS X 0208の対応で変換する（「〜」はU+FF5EではなくU+301Cになる）。
+// JIS X 0208で未定義のNEC特殊文字（13区）とNEC選定IBM拡張文字（89〜92区）は
+// eucJP-msやCP51932と同じくCP932と同じ文字として扱う
+func decodeEUCJP(src byteSource) (rune, error) {
+	b, err := src.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if b < 0x80 {
+		return rune(b), nil
+	}
+
+	// 2バイト目以降を読む（範囲外のバイトは次の文字として読み直す）
+	next := func() (byte, bool) {
+		t, err := src.ReadByte()
+		if err != nil {
+			return 0, false
+		}
+		if t < 0xA1 || t > 0xFE {
+			src.UnreadByte()
+			return 0, false
+		}
+		return t, true
+	}
+
+	switch {
+	case b == 0x8E:
+		// 半角カタカナ
+		t, ok := next()
+		if !ok || t > 0xDF {
+			return utf8.RuneError, nil
+		}
+		return 0xFF61 + rune(t-0xA1), nil
+	case b == 0x8F:
+		// JIS X 0212（補助漢字）は対応表を持たないため置換文字にする
+		if _, ok := next(); ok {
+			next()
+		}
+		return utf8.RuneError, nil
+	case 0xA1 <= b && b <= 0xFE:
+		t, ok := next()
+		if !ok {
+			return utf8.RuneError, nil
+		}
+		row, cell := int(b)-0xA1, int(t)-0xA1
+		if r, ok := jisX0208Overrides[row*94+cell]; ok {
+			return r, nil
+		}
+		return lookupJIS(row, cell), nil
+	}
+	return utf8.RuneError, nil
+}
+
+// lookupJIS 区点（0始まり）に対応する文字を返す（未定義の区点はU+FFFD）
+func lookupJIS(row, cell int) rune {
+	if row < 0 || row >= jisRows || cell < 0 || cell >= 94 {
+		return utf8.RuneError
+	}
+	if r := jisTable[row*94+cell]; r != 0 {
+		return rune(r)
+	}
+	return utf8.RuneError
+}
+
+// scoreEncoding データを指定の方法で変換した際の不正なバイト列の数と、ひらがな・カタカナの数を返す
+func scoreEncoding(data []byte, decode func(byteSource) (rune, error)) (invalid, kana int) {
+	src := bytes.NewReader(data)
+	for {
+		// 末尾で途切れた文字は判定に含めない
+		remaining := src.Len()
+		r, err := decode(src)
+		if err != nil {
+			return invalid, kana
+		}
+		switch {
+		case r == utf8.RuneError && remaining > 2:
+			invalid++
+		case 0x3041 <= r && r <= 0x30FF:
+			kana++
+		}
+	}
+}
+
+// trimIncompleteRune 末尾で途切れたUTF-8の文字を取り除く
+func trimIncompleteRune(data []byte) []byte {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				return data[:i]
+			}
+			break
+		}
+	}
+	return data
+}
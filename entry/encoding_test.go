@@ -0,0 +1,199 @@
+package entry
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// テスト用のShift_JIS（CP932）とEUC-JPのバイト列
+const (
+	shiftJISContent = "TITLE: \x93\xfa\x96{\x8c\xea\x82\xcc\x83^\x83C\x83g\x83\x8b\n-----\nBODY:\n\x82\xb1\x82\xea\x82\xcd\x96{\x95\xb6\x82\xc5\x82\xb7\x81B\xb6\xc0\xb6\xc5\x87@\x81`\n-----\n"
+	eucJPContent    = "TITLE: \xc6\xfc\xcb\xdc\xb8\xec\xa4\xce\xa5\xbf\xa5\xa4\xa5\xc8\xa5\xeb\n-----\nBODY:\n\xa4\xb3\xa4\xec\xa4\xcf\xcb\xdc\xca\xb8\xa4\xc7\xa4\xb9\xa1\xa3\x8e\xb6\x8e\xc0\x8e\xb6\x8e\xc5\xc9\xbd\xbc\xa8\n-----\n"
+)
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Encoding
+	}{
+		{name: "ASCIIのみ", input: "TITLE: ascii\n", expected: EncodingUTF8},
+		{name: "UTF-8", input: "TITLE: 日本語のタイトル\n", expected: EncodingUTF8},
+		{name: "UTF-8（BOM付き）", input: "\xef\xbb\xbfTITLE: 日本語\n", expected: EncodingUTF8},
+		{name: "UTF-8（末尾で文字が途切れている）", input: "TITLE: 日本語"[:14], expected: EncodingUTF8},
+		{name: "Shift_JIS", input: shiftJISContent, expected: EncodingShiftJIS},
+		{name: "EUC-JP", input: eucJPContent, expected: EncodingEUCJP},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := DetectEncoding([]byte(tt.input)); result != tt.expected {
+				t.Errorf("DetectEncoding() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewDecoder(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		encoding Encoding
+		expected string
+		detected Encoding
+	}{
+		{
+			name:     "Shift_JIS（自動判定）",
+			input:    shiftJISContent,
+			encoding: EncodingAuto,
+			expected: "TITLE: 日本語のタイトル\n-----\nBODY:\nこれは本文です。ｶﾀｶﾅ①～\n-----\n",
+			detected: EncodingShiftJIS,
+		},
+		{
+			name:     "EUC-JP（自動判定）",
+			input:    eucJPContent,
+			encoding: EncodingAuto,
+			expected: "TITLE: 日本語のタイトル\n-----\nBODY:\nこれは本文です。ｶﾀｶﾅ表示\n-----\n",
+			detected: EncodingEUCJP,
+		},
+		{
+			name:     "2バイト目が0x5CのShift_JIS",
+			input:    "\x83\x5c\x83t\x83g\x95\x5c",
+			encoding: EncodingShiftJIS,
+			expected: "ソフト表",
+			detected: EncodingShiftJIS,
+		},
+		{
+			name:     "UTF-8のBOMを除去",
+			input:    "\xef\xbb\xbfTITLE: 日本語",
+			encoding: EncodingAuto,
+			expected: "TITLE: 日本語",
+			detected: EncodingUTF8,
+		},
+		{
+			name:     "不正なバイト列は置換文字",
+			input:    "A\x81\x20B\xff",
+			encoding: EncodingShiftJIS,
+			expected: "A� B�",
+			detected: EncodingShiftJIS,
+		},
+		{
+			name:     "末尾で途切れたEUC-JP",
+			input:    "A\xa4",
+			encoding: EncodingEUCJP,
+			expected: "A�",
+			detected: EncodingEUCJP,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, detected, err := NewDecoder(strings.NewReader(tt.input), tt.encoding)
+			if err != nil {
+				t.Fatalf("NewDecoder() failed: %v", err)
+			}
+			if detected != tt.detected {
+				t.Errorf("detected = %v, want %v", detected, tt.detected)
+			}
+			result, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("ReadAll() failed: %v", err)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("decoded = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecodeJISX0208Differences(t *testing.T) {
+	// CP932とJIS X 0208で対応が異なる6文字は、Shift_JISではMicrosoftの対応、EUC-JPではJIS X 0208の対応で変換する
+	tests := []struct {
+		sjis, euc  string
+		cp932, jis rune
+	}{
+		{sjis: "\x81\x60", euc: "\xa1\xc1", cp932: 0xFF5E, jis: 0x301C},
+		{sjis: "\x81\x61", euc: "\xa1\xc2", cp932: 0x2225, jis: 0x2016},
+		{sjis: "\x81\x7c", euc: "\xa1\xdd", cp932: 0xFF0D, jis: 0x2212},
+		{sjis: "\x81\x91", euc: "\xa1\xf1", cp932: 0xFFE0, jis: 0x00A2},
+		{sjis: "\x81\x92", euc: "\xa1\xf2", cp932: 0xFFE1, jis: 0x00A3},
+		{sjis: "\x81\xca", euc: "\xa2\xcc", cp932: 0xFFE2, jis: 0x00AC},
+		// NEC特殊文字（13区）はどちらも同じ文字
+		{sjis: "\x87\x40", euc: "\xad\xa1", cp932: 0x2460, jis: 0x2460},
+	}
+
+	decode := func(input string, enc Encoding) string {
+		r, _, err := NewDecoder(strings.NewReader(input), enc)
+		if err != nil {
+			t.Fatalf("NewDecoder() failed: %v", err)
+		}
+		result, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("ReadAll() failed: %v", err)
+		}
+		return string(result)
+	}
+
+	for _, tt := range tests {
+		if result := decode(tt.sjis, EncodingShiftJIS); result != string(tt.cp932) {
+			t.Errorf("Shift_JIS %q = %U, want %U", tt.sjis, []rune(result), tt.cp932)
+		}
+		if result := decode(tt.euc, EncodingEUCJP); result != string(tt.jis) {
+			t.Errorf("EUC-JP %q = %U, want %U", tt.euc, []rune(result), tt.jis)
+		}
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	tests := map[string]Encoding{
+		"":          EncodingAuto,
+		"auto":      EncodingAuto,
+		"UTF-8":     EncodingUTF8,
+		"utf8":      EncodingUTF8,
+		"Shift_JIS": EncodingShiftJIS,
+		"sjis":      EncodingShiftJIS,
+		"CP932":     EncodingShiftJIS,
+		"EUC-JP":    EncodingEUCJP,
+		"eucjp":     EncodingEUCJP,
+	}
+
+	for name, expected := range tests {
+		result, err := ParseEncoding(name)
+		if err != nil {
+			t.Errorf("ParseEncoding(%q) failed: %v", name, err)
+		}
+		if result != expected {
+			t.Errorf("ParseEncoding(%q) = %v, want %v", name, result, expected)
+		}
+	}
+
+	if _, err := ParseEncoding("iso-2022-jp"); err == nil {
+		t.Error("ParseEncoding(iso-2022-jp) expected error, got nil")
+	}
+}
+
+func TestParserEncoding(t *testing.T) {
+	p := NewParser(strings.NewReader(shiftJISContent))
+	entries, err := collectEntries(p)
+	if err != nil {
+		t.Fatalf("collectEntries failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Title != "日本語のタイトル" {
+		t.Fatalf("Expected decoded title, got %+v", entries)
+	}
+	if p.DetectedEncoding() != EncodingShiftJIS {
+		t.Errorf("DetectedEncoding() = %v, want Shift_JIS", p.DetectedEncoding())
+	}
+
+	// 文字コードを明示した場合は判定しない
+	p = NewParser(strings.NewReader(eucJPContent))
+	p.Encoding = EncodingEUCJP
+	entries, err = collectEntries(p)
+	if err != nil {
+		t.Fatalf("collectEntries failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Body != "これは本文です。ｶﾀｶﾅ表示" {
+		t.Errorf("Expected decoded body, got %+v", entries)
+	}
+}
@@ -1,21 +1,64 @@
 package entry
 
 import (
-	"bufio"
+	"io"
 	"os"
-	"strings"
+	"time"
 )
 
 // Entry MovableType形式のエントリーを表現する構造体
 type Entry struct {
-	Author   string
-	Title    string
-	Basename string
-	Status   string
-	Date     string
-	Category string
-	Body     string
-	ImageURL string
+	Author        string
+	Title         string
+	Basename      string
+	Status        Status
+	Date          string
+	DateTime      time.Time
+	AllowComments Flag
+	AllowPings    Flag
+	ConvertBreaks ConvertBreaks
+	Categories    []Category
+	Body          string
+	ExtendedBody  string
+	Excerpt       string
+	Keywords      string
+	ImageURL      string
+	Comments      []Comment
+	Pings         []Ping
+	Extra         []Field // 解析対象外のヘッダー（NO ENTRYや独自キーなど）を出現順に保持
+}
+
+// Field ヘッダー行のキーと値の組
+type Field struct {
+	Key   string
+	Value string
+	// Position エントリーのヘッダー行の中で何行目にあったか（1始まり、0なら位置の記録なし）
+	Position int
+}
+
+// ExtraValue Extraから最初に一致するキーの値を返す
+func (e Entry) ExtraValue(key string) (string, bool) {
+	for _, f := range e.Extra {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// sectionField 複数行セクション名に対応するフィールドを返す（未知のセクションはnil）
+func (e *Entry) sectionField(name string) *string {
+	switch name {
+	case "BODY":
+		return &e.Body
+	case "EXTENDED BODY":
+		return &e.ExtendedBody
+	case "EXCERPT":
+		return &e.Excerpt
+	case "KEYWORDS":
+		return &e.Keywords
+	}
+	return nil
 }
 
 // ParseEntries MTファイルを解析してエントリー一覧を返す
@@ -26,71 +69,24 @@ func ParseEntries(filename string) ([]Entry, error) {
 	}
 	defer file.Close()
 
-	var entries []Entry
-	var currentEntry Entry
-	var inBody bool
-	var bodyLines []string
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		// エントリー区切り
-		if line == "--------" {
-			if currentEntry.Title != "" {
-				currentEntry.Body = strings.Join(bodyLines, "\n")
-				entries = append(entries, currentEntry)
-			}
-			currentEntry = Entry{}
-			inBody = false
-			bodyLines = []string{}
-			continue
-		}
-
-		// ボディ開始
-		if line == "BODY:" {
-			inBody = true
-			continue
-		}
-
-		// ヘッダー終了
-		if line == "-----" && !inBody {
-			continue
-		}
+	p := NewParser(file)
+	p.Filename = filename
+	return collectEntries(p)
+}
 
-		// ボディ部分
-		if inBody {
-			if line == "-----" {
-				inBody = false
-				continue
-			}
-			bodyLines = append(bodyLines, line)
-			continue
-		}
+// ParseReader io.ReaderからMT形式のデータを解析してエントリー一覧を返す
+func ParseReader(r io.Reader) ([]Entry, error) {
+	return collectEntries(NewParser(r))
+}
 
-		// メタデータの解析
-		if strings.HasPrefix(line, "AUTHOR: ") {
-			currentEntry.Author = strings.TrimPrefix(line, "AUTHOR: ")
-		} else if strings.HasPrefix(line, "TITLE: ") {
-			currentEntry.Title = strings.TrimPrefix(line, "TITLE: ")
-		} else if strings.HasPrefix(line, "BASENAME: ") {
-			currentEntry.Basename = strings.TrimPrefix(line, "BASENAME: ")
-		} else if strings.HasPrefix(line, "STATUS: ") {
-			currentEntry.Status = strings.TrimPrefix(line, "STATUS: ")
-		} else if strings.HasPrefix(line, "DATE: ") {
-			currentEntry.Date = strings.TrimPrefix(line, "DATE: ")
-		} else if strings.HasPrefix(line, "CATEGORY: ") {
-			currentEntry.Category = strings.TrimPrefix(line, "CATEGORY: ")
-		} else if strings.HasPrefix(line, "IMAGE: ") {
-			currentEntry.ImageURL = strings.TrimPrefix(line, "IMAGE: ")
+// collectEntries パーサーから全エントリーを読み出す
+func collectEntries(p *Parser) ([]Entry, error) {
+	var entries []Entry
+	for e, err := range p.All() {
+		if err != nil {
+			return entries, err
 		}
+		entries = append(entries, e)
 	}
-
-	// 最後のエントリーを追加
-	if currentEntry.Title != "" {
-		currentEntry.Body = strings.Join(bodyLines, "\n")
-		entries = append(entries, currentEntry)
-	}
-
-	return entries, scanner.Err()
+	return entries, nil
 }
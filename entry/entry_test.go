@@ -3,6 +3,7 @@ package entry
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -74,8 +75,8 @@ BODY:
 	if entries[0].Date != "01/01/2023 12:00:00 AM" {
 		t.Errorf("Expected Date '01/01/2023 12:00:00 AM', got '%s'", entries[0].Date)
 	}
-	if entries[0].Category != "テストカテゴリ" {
-		t.Errorf("Expected Category 'テストカテゴリ', got '%s'", entries[0].Category)
+	if len(entries[0].Categories) != 1 || entries[0].Categories[0].Name != "テストカテゴリ" {
+		t.Errorf("Expected Categories ['テストカテゴリ'], got %v", entries[0].Categories)
 	}
 	if entries[0].Body != "これはテスト用のエントリー本文です。\n複数行のテストです。" {
 		t.Errorf("Expected Body content, got '%s'", entries[0].Body)
@@ -100,8 +101,8 @@ BODY:
 	if entries[1].Date != "01/02/2023 12:00:00 AM" {
 		t.Errorf("Expected Date '01/02/2023 12:00:00 AM', got '%s'", entries[1].Date)
 	}
-	if entries[1].Category != "別のカテゴリ" {
-		t.Errorf("Expected Category '別のカテゴリ', got '%s'", entries[1].Category)
+	if len(entries[1].Categories) != 1 || entries[1].Categories[0].Name != "別のカテゴリ" {
+		t.Errorf("Expected Categories ['別のカテゴリ'], got %v", entries[1].Categories)
 	}
 	if entries[1].Body != "2番目のテストエントリーです。\n画像URLも含まれています。" {
 		t.Errorf("Expected Body content, got '%s'", entries[1].Body)
@@ -183,3 +184,237 @@ BODY:
 		t.Errorf("Expected Body content, got '%s'", entries[0].Body)
 	}
 }
+
+func TestParseEntriesMultiLineSections(t *testing.T) {
+	// BODY以外の複数行セクションを含むエントリーのテスト
+	testContent := `AUTHOR: section_author
+TITLE: Section Entry
+-----
+BODY:
+本文です。
+-----
+EXTENDED BODY:
+追記の1行目です。
+追記の2行目です。
+-----
+EXCERPT:
+概要です。
+-----
+KEYWORDS:
+go, mt
+-----
+--------`
+
+	tmpDir, err := os.MkdirTemp("", "mttohmd_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	testFile := filepath.Join(tmpDir, "sections.txt")
+	err = os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ParseEntries(testFile)
+	if err != nil {
+		t.Fatalf("ParseEntries failed: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+
+	if entries[0].Body != "本文です。" {
+		t.Errorf("Expected Body content, got '%s'", entries[0].Body)
+	}
+	if entries[0].ExtendedBody != "追記の1行目です。\n追記の2行目です。" {
+		t.Errorf("Expected ExtendedBody content, got '%s'", entries[0].ExtendedBody)
+	}
+	if entries[0].Excerpt != "概要です。" {
+		t.Errorf("Expected Excerpt content, got '%s'", entries[0].Excerpt)
+	}
+	if entries[0].Keywords != "go, mt" {
+		t.Errorf("Expected Keywords content, got '%s'", entries[0].Keywords)
+	}
+}
+
+func TestParseEntriesComments(t *testing.T) {
+	// COMMENTセクションを含むエントリーのテスト
+	testContent := `TITLE: Comment Entry
+-----
+BODY:
+本文です。
+-----
+COMMENT:
+AUTHOR: commenter
+EMAIL: commenter@example.com
+IP: 192.0.2.1
+URL: https://example.com/
+DATE: 01/02/2023 10:00:00 AM
+最初のコメントです。
+2行目です。
+-----
+COMMENT:
+AUTHOR: another
+EMAIL:
+DATE: 01/03/2023 11:00:00 AM
+2つ目のコメントです。
+-----
+--------`
+
+	tmpDir, err := os.MkdirTemp("", "mttohmd_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	testFile := filepath.Join(tmpDir, "comments.txt")
+	err = os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ParseEntries(testFile)
+	if err != nil {
+		t.Fatalf("ParseEntries failed: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Body != "本文です。" {
+		t.Errorf("Expected Body content, got '%s'", entries[0].Body)
+	}
+
+	comments := entries[0].Comments
+	if len(comments) != 2 {
+		t.Fatalf("Expected 2 comments, got %d", len(comments))
+	}
+
+	expected := Comment{
+		Author: "commenter",
+		Email:  "commenter@example.com",
+		IP:     "192.0.2.1",
+		URL:    "https://example.com/",
+		Date:   "01/02/2023 10:00:00 AM",
+		Body:   "最初のコメントです。\n2行目です。",
+	}
+	if comments[0] != expected {
+		t.Errorf("Expected comment %+v, got %+v", expected, comments[0])
+	}
+
+	if comments[1].Author != "another" {
+		t.Errorf("Expected Author 'another', got '%s'", comments[1].Author)
+	}
+	if comments[1].Email != "" {
+		t.Errorf("Expected empty Email, got '%s'", comments[1].Email)
+	}
+	if comments[1].Body != "2つ目のコメントです。" {
+		t.Errorf("Expected comment Body, got '%s'", comments[1].Body)
+	}
+}
+
+func TestParseEntriesPings(t *testing.T) {
+	// PINGセクションを含むエントリーのテスト
+	testContent := `TITLE: Ping Entry
+-----
+BODY:
+本文です。
+-----
+PING:
+TITLE: 言及した記事
+URL: https://example.com/entry/1
+IP: 192.0.2.2
+BLOG NAME: Example Blog
+DATE: 01/04/2023 09:00:00 AM
+トラックバックの抜粋です。
+-----
+--------`
+
+	tmpDir, err := os.MkdirTemp("", "mttohmd_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	testFile := filepath.Join(tmpDir, "pings.txt")
+	err = os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ParseEntries(testFile)
+	if err != nil {
+		t.Fatalf("ParseEntries failed: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Title != "Ping Entry" {
+		t.Errorf("Expected Title 'Ping Entry', got '%s'", entries[0].Title)
+	}
+
+	pings := entries[0].Pings
+	if len(pings) != 1 {
+		t.Fatalf("Expected 1 ping, got %d", len(pings))
+	}
+
+	expected := Ping{
+		Title:    "言及した記事",
+		URL:      "https://example.com/entry/1",
+		IP:       "192.0.2.2",
+		BlogName: "Example Blog",
+		Date:     "01/04/2023 09:00:00 AM",
+		Excerpt:  "トラックバックの抜粋です。",
+	}
+	if pings[0] != expected {
+		t.Errorf("Expected ping %+v, got %+v", expected, pings[0])
+	}
+}
+
+func TestParseEntriesMultipleCategories(t *testing.T) {
+	// 複数のCATEGORY行とPRIMARY CATEGORY行のテスト
+	testContent := `TITLE: Category Entry
+PRIMARY CATEGORY: Go
+CATEGORY: Go
+CATEGORY: プログラミング
+CATEGORY: テスト
+-----
+BODY:
+本文です。
+-----
+--------`
+
+	tmpDir, err := os.MkdirTemp("", "mttohmd_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	testFile := filepath.Join(tmpDir, "categories.txt")
+	err = os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ParseEntries(testFile)
+	if err != nil {
+		t.Fatalf("ParseEntries failed: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+
+	expected := []Category{
+		{Name: "Go", Primary: true},
+		{Name: "プログラミング"},
+		{Name: "テスト"},
+	}
+	if !reflect.DeepEqual(entries[0].Categories, expected) {
+		t.Errorf("Expected Categories %v, got %v", expected, entries[0].Categories)
+	}
+}
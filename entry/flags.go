@@ -0,0 +1,88 @@
+package entry
+
+// Status エントリーの公開状態
+type Status string
+
+const (
+	StatusPublish Status = "Publish"
+	StatusDraft   Status = "Draft"
+	StatusFuture  Status = "Future"
+)
+
+// Valid MT形式で定義された公開状態かどうか
+func (s Status) Valid() bool {
+	switch s {
+	case StatusPublish, StatusDraft, StatusFuture:
+		return true
+	}
+	return false
+}
+
+// Flag ALLOW COMMENTS / ALLOW PINGSなど0か1で指定する設定（未指定を区別する）
+type Flag int
+
+const (
+	FlagUnset Flag = iota
+	FlagOff
+	FlagOn
+)
+
+// parseFlag 0/1の文字列をFlagに変換
+func parseFlag(s string) (Flag, bool) {
+	switch s {
+	case "0":
+		return FlagOff, true
+	case "1":
+		return FlagOn, true
+	}
+	return FlagUnset, false
+}
+
+// Bool 有効かどうか（未指定は無効として扱う）
+func (f Flag) Bool() bool {
+	return f == FlagOn
+}
+
+// String MT形式での表記を返す（未指定は空）
+func (f Flag) String() string {
+	switch f {
+	case FlagOff:
+		return "0"
+	case FlagOn:
+		return "1"
+	}
+	return ""
+}
+
+// ConvertBreaks 本文の改行の扱い（テキストフォーマット）
+type ConvertBreaks string
+
+const (
+	ConvertBreaksNone       ConvertBreaks = "0"
+	ConvertBreaksOn         ConvertBreaks = "1"
+	ConvertBreaksDefault    ConvertBreaks = "__default__"
+	ConvertBreaksMarkdown   ConvertBreaks = "markdown"
+	ConvertBreaksSmartyPant ConvertBreaks = "markdown_with_smartypants"
+	ConvertBreaksRichText   ConvertBreaks = "richtext"
+	ConvertBreaksTextile    ConvertBreaks = "textile_2"
+)
+
+// Valid MT形式で定義されたテキストフォーマットかどうか
+func (c ConvertBreaks) Valid() bool {
+	switch c {
+	case ConvertBreaksNone, ConvertBreaksOn, ConvertBreaksDefault, ConvertBreaksMarkdown,
+		ConvertBreaksSmartyPant, ConvertBreaksRichText, ConvertBreaksTextile:
+		return true
+	}
+	return false
+}
+
+// ConvertsLineBreaks 本文中の改行を改行として扱うフォーマットかどうか
+func (c ConvertBreaks) ConvertsLineBreaks() bool {
+	return c == ConvertBreaksOn || c == ConvertBreaksDefault
+}
+
+// IsMarkdown 本文がMarkdownで書かれているかどうか
+func (c ConvertBreaks) IsMarkdown() bool {
+	return c == ConvertBreaksMarkdown || c == ConvertBreaksSmartyPant
+}
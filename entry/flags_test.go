@@ -0,0 +1,73 @@
+package entry
+
+import "testing"
+
+func TestStatusValid(t *testing.T) {
+	for _, s := range []Status{StatusPublish, StatusDraft, StatusFuture} {
+		if !s.Valid() {
+			t.Errorf("Status(%q).Valid() = false, want true", s)
+		}
+	}
+	for _, s := range []Status{"", "publish", "Hidden"} {
+		if s.Valid() {
+			t.Errorf("Status(%q).Valid() = true, want false", s)
+		}
+	}
+}
+
+func TestParseFlag(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Flag
+		ok       bool
+	}{
+		{"0", FlagOff, true},
+		{"1", FlagOn, true},
+		{"", FlagUnset, false},
+		{"yes", FlagUnset, false},
+	}
+
+	for _, tt := range tests {
+		result, ok := parseFlag(tt.input)
+		if result != tt.expected || ok != tt.ok {
+			t.Errorf("parseFlag(%q) = %v, %v, want %v, %v", tt.input, result, ok, tt.expected, tt.ok)
+		}
+		if ok && result.String() != tt.input {
+			t.Errorf("Flag.String() = %q, want %q", result.String(), tt.input)
+		}
+	}
+
+	if FlagUnset.String() != "" || FlagUnset.Bool() {
+		t.Error("FlagUnset should be empty and false")
+	}
+}
+
+func TestConvertBreaks(t *testing.T) {
+	tests := []struct {
+		value      ConvertBreaks
+		valid      bool
+		lineBreaks bool
+		markdown   bool
+	}{
+		{ConvertBreaksNone, true, false, false},
+		{ConvertBreaksOn, true, true, false},
+		{ConvertBreaksDefault, true, true, false},
+		{ConvertBreaksMarkdown, true, false, true},
+		{ConvertBreaksSmartyPant, true, false, true},
+		{ConvertBreaksRichText, true, false, false},
+		{"", false, false, false},
+		{"unknown", false, false, false},
+	}
+
+	for _, tt := range tests {
+		if tt.value.Valid() != tt.valid {
+			t.Errorf("ConvertBreaks(%q).Valid() = %v, want %v", tt.value, !tt.valid, tt.valid)
+		}
+		if tt.value.ConvertsLineBreaks() != tt.lineBreaks {
+			t.Errorf("ConvertBreaks(%q).ConvertsLineBreaks() = %v, want %v", tt.value, !tt.lineBreaks, tt.lineBreaks)
+		}
+		if tt.value.IsMarkdown() != tt.markdown {
+			t.Errorf("ConvertBreaks(%q).IsMarkdown() = %v, want %v", tt.value, !tt.markdown, tt.markdown)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+//go:build ignore
+
+// gen_jis_table.go UnicodeコンソーシアムのCP932の対応表（CP932.TXT）からjis_table.goを生成する
+//
+//	go run gen_jis_table.go                      # unicode.orgから取得
+//	go run gen_jis_table.go -input CP932.TXT     # ダウンロード済みのファイルを使う
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// cp932URL CP932.TXTの配布元
+const cp932URL = "https://www.unicode.org/Public/MAPPINGS/VENDORS/MICSFT/WINDOWS/CP932.TXT"
+
+// jisRows 出力する区の数（JIS X 0208の94区とCP932の拡張領域、encoding.goのjisRowsと同じ）
+const jisRows = 120
+
+func main() {
+	input := flag.String("input", "", "CP932.TXTのパス（省略時は "+cp932URL+" から取得）")
+	output := flag.String("output", "jis_table.go", "出力するファイル")
+	flag.Parse()
+
+	src, err := open(*input)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer src.Close()
+
+	table, err := readTable(src)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	code, err := format.Source(generate(table))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, code, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// open 対応表を開く（パスが空ならunicode.orgから取得）
+func open(path string) (io.ReadCloser, error) {
+	if path != "" {
+		return os.Open(path)
+	}
+	resp, err := http.Get(cp932URL)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %s", cp932URL, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// readTable 「0x8140<TAB>0x3000<TAB>#...」形式の行から、2バイト文字を区点の順に並べた表を作る
+func readTable(r io.Reader) ([]uint16, error) {
+	table := make([]uint16, jisRows*94)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		sjis, err := strconv.ParseUint(fields[0], 0, 16)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", scanner.Text(), err)
+		}
+		u, err := strconv.ParseUint(fields[1], 0, 16)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", scanner.Text(), err)
+		}
+		if sjis <= 0xFF {
+			continue
+		}
+
+		row, cell := rowCell(byte(sjis>>8), byte(sjis))
+		if row < 0 || row >= jisRows || cell < 0 || cell >= 94 {
+			return nil, fmt.Errorf("範囲外の文字です: %#x", sjis)
+		}
+		table[row*94+cell] = uint16(u)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// CP932.TXTに含まれない外字領域（0xF040〜0xF9FC）はWindowsと同じく私用領域のU+E000から順に割り当てる
+	u := uint16(0xE000)
+	for b := 0xF0; b <= 0xF9; b++ {
+		for t := 0x40; t <= 0xFC; t++ {
+			if t == 0x7F {
+				continue
+			}
+			row, cell := rowCell(byte(b), byte(t))
+			table[row*94+cell] = u
+			u++
+		}
+	}
+	return table, nil
+}
+
+// rowCell Shift_JISの2バイトを0始まりの区点に変換（encoding.goのdecodeShiftJISと同じ計算）
+func rowCell(b, t byte) (int, int) {
+	row := int(b) - 0x81
+	if b >= 0xE0 {
+		row = int(b) - 0xC1
+	}
+	row *= 2
+	if t >= 0x9F {
+		return row + 1, int(t) - 0x9F
+	}
+	cell := int(t) - 0x40
+	if t > 0x7F {
+		cell--
+	}
+	return row, cell
+}
+
+// generate 表をGoのソースコードとして出力
+func generate(table []uint16) []byte {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by gen_jis_table.go from CP932.TXT. DO NOT EDIT.\n\n")
+	b.WriteString("package entry\n\n")
+	b.WriteString("// jisTable JIS X 0208の区点（CP932のNEC特殊文字・IBM拡張文字を含む120区）からUnicodeへの対応表\n")
+	b.WriteString("// 添字は (区-1)*94 + (点-1)、未定義の区点は0\n")
+	b.WriteString("var jisTable = [jisRows * 94]uint16{\n")
+	for i, u := range table {
+		if i%12 == 0 {
+			b.WriteString("\t")
+		}
+		fmt.Fprintf(&b, "0x%04X,", u)
+		if i%12 == 11 || i == len(table)-1 {
+			b.WriteString("\n")
+		} else {
+			b.WriteString(" ")
+		}
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
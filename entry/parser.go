@@ -0,0 +1,440 @@
+package entry
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+	"time"
+)
+
+// DefaultMaxLineSize Parser.MaxLineSizeを指定しない場合の1行の最大バイト数
+const DefaultMaxLineSize = 64 << 20
+
+// Parser io.ReaderからMT形式のエントリーを1件ずつ読み出すパーサー
+// 読み込み中のエントリーは本文ごとメモリーに保持するため、使用するメモリーは最も大きいエントリーの大きさに比例する。
+// 1行はMaxLineSizeバイトまでで、それを超える行があると解析を中止する
+type Parser struct {
+	// Filename 診断メッセージに表示するファイル名
+	Filename string
+	// Strict 診断をエラーとして扱い、最初の問題で解析を中止する
+	Strict bool
+	// Location DATEを解釈するタイムゾーン（nilの場合はローカルタイム）
+	Location *time.Location
+	// Encoding 入力の文字コード（EncodingAutoの場合は先頭を読んで判定する）
+	Encoding Encoding
+	// MaxLineSize 1行の最大バイト数（0の場合はDefaultMaxLineSize）
+	MaxLineSize int
+
+	src         io.Reader
+	detected    Encoding
+	reader      *bufio.Reader
+	lookahead   []string
+	readErr     error
+	done        bool
+	err         error
+	line        int
+	diagnostics []Diagnostic
+
+	current         Entry
+	primaryCategory *string
+	// 読み込み中のエントリーの番号（1始まり）と内容の有無
+	entryIndex int
+	entryLines int
+	// 読み込み中のエントリーでこれまでに読んだヘッダー行の数
+	headerLines int
+	// 読み込み中の複数行セクション名（セクション外では空）と開始行
+	section      string
+	sectionLine  int
+	sectionLines []string
+}
+
+// NewParser io.Readerを読み込むパーサーを作成
+func NewParser(r io.Reader) *Parser {
+	return &Parser{src: r, entryIndex: 1}
+}
+
+// DetectedEncoding 解析に使用した文字コードを返す（読み込み開始前はEncodingAuto）
+func (p *Parser) DetectedEncoding() Encoding {
+	return p.detected
+}
+
+// Diagnostics これまでの解析で見つかった問題の一覧を返す
+func (p *Parser) Diagnostics() []Diagnostic {
+	return p.diagnostics
+}
+
+// Next 次のエントリーを返す（全エントリーを読み終えた場合はio.EOF）
+func (p *Parser) Next() (Entry, error) {
+	if p.done {
+		return Entry{}, io.EOF
+	}
+
+	// 最初の読み込み時に文字コードを決めてUTF-8に変換する
+	if p.reader == nil {
+		r, enc, err := NewDecoder(p.src, p.Encoding)
+		if err != nil {
+			return Entry{}, p.fail(err)
+		}
+		p.reader = bufio.NewReader(r)
+		p.detected = enc
+	}
+
+	for {
+		line, err := p.readLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Entry{}, p.fail(err)
+		}
+
+		// エントリー区切り
+		if line == "--------" && p.isEntrySeparator() {
+			e, ok := p.finishEntry()
+			if p.err != nil {
+				return Entry{}, p.fail(p.err)
+			}
+			if ok {
+				return e, nil
+			}
+			continue
+		}
+
+		p.parseLine(line)
+		if p.err != nil {
+			return Entry{}, p.fail(p.err)
+		}
+	}
+
+	p.done = true
+
+	// 最後のエントリーを返す
+	e, ok := p.finishEntry()
+	if p.err != nil {
+		return Entry{}, p.err
+	}
+	if ok {
+		return e, nil
+	}
+	return Entry{}, io.EOF
+}
+
+// All 全エントリーを順に返すイテレーター（エラーが発生した場合はそこで終了）
+func (p *Parser) All() iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		for {
+			e, err := p.Next()
+			if err == io.EOF {
+				return
+			}
+			if !yield(e, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// fail 解析を終了してエラーを返す
+func (p *Parser) fail(err error) error {
+	p.done = true
+	p.err = err
+	return err
+}
+
+// warnf 診断を記録（strictモードではエラーとして解析を中止させる）
+func (p *Parser) warnf(line int, format string, args ...any) {
+	d := Diagnostic{
+		File:    p.Filename,
+		Line:    line,
+		Entry:   p.entryIndex,
+		Message: fmt.Sprintf(format, args...),
+	}
+	p.diagnostics = append(p.diagnostics, d)
+	if p.Strict && p.err == nil {
+		p.err = &d
+	}
+}
+
+// readLine 次の1行を返す（先読み済みの行があればそれを返す）
+func (p *Parser) readLine() (string, error) {
+	p.fill(1)
+	if len(p.lookahead) == 0 {
+		return "", p.readErr
+	}
+	line := p.lookahead[0]
+	p.lookahead = p.lookahead[1:]
+	p.line++
+	return line, nil
+}
+
+// peek i行先（0始まり）の行を読まずに返す（ファイル末尾を越える場合はfalse）
+func (p *Parser) peek(i int) (string, bool) {
+	p.fill(i + 1)
+	if i < len(p.lookahead) {
+		return p.lookahead[i], true
+	}
+	return "", false
+}
+
+// fill 先読みの行がn行になるまで読み込む
+func (p *Parser) fill(n int) {
+	for len(p.lookahead) < n && p.readErr == nil {
+		line, err := p.readRawLine()
+		if err != nil {
+			p.readErr = err
+			return
+		}
+		p.lookahead = append(p.lookahead, line)
+	}
+}
+
+// readRawLine 1行を読み込んで改行文字を除いて返す
+// bufio.Scannerの上限（64KB）を超える行も、MaxLineSizeまでは1行として読み込む
+// （base64で埋め込まれた画像などの巨大な行に対応）
+func (p *Parser) readRawLine() (string, error) {
+	limit := p.MaxLineSize
+	if limit <= 0 {
+		limit = DefaultMaxLineSize
+	}
+
+	var line []byte
+	for {
+		chunk, err := p.reader.ReadSlice('\n')
+		line = append(line, chunk...)
+		if err == bufio.ErrBufferFull {
+			// 改行が見つかるまで読み進める（上限を超えたらその時点で中止）
+			if len(line) > limit {
+				return "", p.lineTooLong(limit)
+			}
+			continue
+		}
+		if err == io.EOF && len(line) > 0 {
+			// 改行で終わらない最終行
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		break
+	}
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if len(line) > limit {
+		return "", p.lineTooLong(limit)
+	}
+	return string(line), nil
+}
+
+// lineTooLong 読み込み中の行がMaxLineSizeを超えたことを示すエラーを返す
+func (p *Parser) lineTooLong(limit int) error {
+	return &Diagnostic{
+		File:    p.Filename,
+		Line:    p.line + len(p.lookahead) + 1,
+		Entry:   p.entryIndex,
+		Message: fmt.Sprintf("1行が長すぎます（最大%dバイト）", limit),
+	}
+}
+
+// isEntrySeparator 直前に読んだ「--------」がエントリー区切りかどうか
+// 複数行セクションの中では、次の行がファイル末尾かMT形式のヘッダー行の場合のみ区切りとみなす
+// （本文中の「Q: 質問」や「NOTE: 注意」のような行の前の区切り線は本文として扱う）
+func (p *Parser) isEntrySeparator() bool {
+	if p.section == "" {
+		return true
+	}
+	next, _, ok := p.peekNonBlank(0)
+	return !ok || isHeaderLine(next)
+}
+
+// isSectionEnd 直前に読んだ「-----」が複数行セクションの終わりかどうか
+// 次の行がファイル末尾・セクション開始・エントリー区切りの場合のみ終わりとみなし、
+// それ以外は本文中の水平線などとして扱う
+func (p *Parser) isSectionEnd() bool {
+	next, i, ok := p.peekNonBlank(0)
+	if !ok {
+		return true
+	}
+	if name, found := strings.CutSuffix(next, ":"); found && isSectionName(name) {
+		return true
+	}
+	if next != "--------" {
+		return false
+	}
+	afterNext, _, ok := p.peekNonBlank(i + 1)
+	return !ok || isHeaderLine(afterNext)
+}
+
+// peekNonBlank i行先（0始まり）以降で最初の空行でない行とその位置を返す（見つからなければfalse）
+func (p *Parser) peekNonBlank(i int) (string, int, bool) {
+	for ; ; i++ {
+		line, ok := p.peek(i)
+		if !ok {
+			return "", i, false
+		}
+		if strings.TrimSpace(line) != "" {
+			return line, i, true
+		}
+	}
+}
+
+// isSectionName 複数行セクションの名前かどうか
+func isSectionName(name string) bool {
+	switch name {
+	case "BODY", "EXTENDED BODY", "EXCERPT", "KEYWORDS", "COMMENT", "PING":
+		return true
+	}
+	return false
+}
+
+// isHeaderKey MT形式で定義されたエントリーのヘッダーのキーかどうか
+func isHeaderKey(key string) bool {
+	switch key {
+	case "AUTHOR", "TITLE", "BASENAME", "STATUS", "ALLOW COMMENTS", "ALLOW PINGS", "CONVERT BREAKS",
+		"DATE", "PRIMARY CATEGORY", "CATEGORY", "IMAGE", "NO ENTRY", "TAGS":
+		return true
+	}
+	return false
+}
+
+// isHeaderLine 「KEY: 値」形式のMT形式のヘッダー行かどうか（独自のキーはエントリーの始まりとみなさない）
+func isHeaderLine(line string) bool {
+	key, _, ok := strings.Cut(line, ":")
+	return ok && isHeaderKey(key)
+}
+
+// parseLine エントリー区切り以外の1行を解析
+func (p *Parser) parseLine(line string) {
+	if strings.TrimSpace(line) != "" {
+		p.entryLines++
+	}
+
+	// 複数行セクション部分
+	if p.section != "" {
+		if line == "-----" && p.isSectionEnd() {
+			p.flushSection()
+			return
+		}
+		p.sectionLines = append(p.sectionLines, line)
+		return
+	}
+
+	// 複数行セクション開始（BODY: / EXTENDED BODY: / EXCERPT: / KEYWORDS: / COMMENT: / PING:）
+	if name, ok := strings.CutSuffix(line, ":"); ok {
+		if isSectionName(name) {
+			p.section = name
+			p.sectionLine = p.line
+			return
+		}
+	}
+
+	// ヘッダー終了と空行
+	if line == "-----" || strings.TrimSpace(line) == "" {
+		return
+	}
+
+	// メタデータの解析
+	key, value, ok := strings.Cut(line, ":")
+	if !ok {
+		p.warnf(p.line, "解釈できない行です: %q", line)
+		return
+	}
+	value = strings.TrimPrefix(value, " ")
+	p.headerLines++
+
+	switch key {
+	case "AUTHOR":
+		p.current.Author = value
+	case "TITLE":
+		p.current.Title = value
+	case "BASENAME":
+		p.current.Basename = value
+	case "STATUS":
+		p.current.Status = Status(value)
+		if !p.current.Status.Valid() {
+			p.warnf(p.line, "不正な STATUS です: %q", value)
+		}
+	case "ALLOW COMMENTS", "ALLOW PINGS":
+		flag, ok := parseFlag(value)
+		if !ok {
+			// 解釈できない値は失わないようにそのまま保持
+			p.warnf(p.line, "不正な %s です: %q", key, value)
+			p.addExtra(key, value)
+		} else if key == "ALLOW COMMENTS" {
+			p.current.AllowComments = flag
+		} else {
+			p.current.AllowPings = flag
+		}
+	case "CONVERT BREAKS":
+		p.current.ConvertBreaks = ConvertBreaks(value)
+		if !p.current.ConvertBreaks.Valid() {
+			p.warnf(p.line, "不正な CONVERT BREAKS です: %q", value)
+		}
+	case "DATE":
+		p.current.Date = value
+		t, err := ParseDate(value, p.Location)
+		if err != nil {
+			p.warnf(p.line, "%v", err)
+		}
+		p.current.DateTime = t
+	case "PRIMARY CATEGORY":
+		// CATEGORY行の並びを保つため、主カテゴリーの設定はエントリーの終わりで行う
+		p.primaryCategory = &value
+	case "CATEGORY":
+		p.current.addCategory(value)
+	case "IMAGE":
+		p.current.ImageURL = value
+	case "NO ENTRY", "TAGS":
+		// MT形式で定義されているが解析対象外のキーはそのまま保持
+		p.addExtra(key, value)
+	default:
+		p.warnf(p.line, "未知のキーです: %s", key)
+		p.addExtra(key, value)
+	}
+}
+
+// addExtra 解析対象外のヘッダーを、ヘッダー中の位置とともにExtraに追加
+func (p *Parser) addExtra(key, value string) {
+	p.current.Extra = append(p.current.Extra, Field{Key: key, Value: value, Position: p.headerLines})
+}
+
+// flushSection 読み込み中のセクションを確定させる
+func (p *Parser) flushSection() {
+	switch p.section {
+	case "":
+	case "COMMENT":
+		p.current.Comments = append(p.current.Comments, parseComment(p.sectionLines))
+	case "PING":
+		p.current.Pings = append(p.current.Pings, parsePing(p.sectionLines))
+	default:
+		*p.current.sectionField(p.section) = strings.Join(p.sectionLines, "\n")
+	}
+	p.section = ""
+	p.sectionLines = nil
+}
+
+// finishEntry 読み込み中のエントリーを確定させる（タイトルのないエントリーは捨てる）
+func (p *Parser) finishEntry() (Entry, bool) {
+	if p.section != "" {
+		p.warnf(p.sectionLine, "%s セクションが ----- で閉じられていません", p.section)
+	}
+	p.flushSection()
+	if p.primaryCategory != nil {
+		p.current.setPrimaryCategory(*p.primaryCategory)
+		p.primaryCategory = nil
+	}
+
+	e := p.current
+	ok := e.Title != ""
+	if !ok && p.entryLines > 0 {
+		p.warnf(p.line, "TITLE のないエントリーを読み飛ばしました")
+	}
+
+	p.current = Entry{}
+	p.entryIndex++
+	p.entryLines = 0
+	p.headerLines = 0
+	return e, ok
+}
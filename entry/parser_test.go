@@ -0,0 +1,469 @@
+package entry
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const parserTestContent = `TITLE: Entry 1
+-----
+BODY:
+本文1
+-----
+--------
+TITLE: Entry 2
+-----
+BODY:
+本文2
+-----
+--------
+TITLE: Entry 3
+-----
+BODY:
+本文3
+-----
+--------
+`
+
+func TestParserNext(t *testing.T) {
+	p := NewParser(strings.NewReader(parserTestContent))
+
+	for _, title := range []string{"Entry 1", "Entry 2", "Entry 3"} {
+		e, err := p.Next()
+		if err != nil {
+			t.Fatalf("Next() failed: %v", err)
+		}
+		if e.Title != title {
+			t.Errorf("Expected Title %q, got %q", title, e.Title)
+		}
+	}
+
+	// 読み終えた後は何度呼んでもio.EOF
+	for range 2 {
+		if _, err := p.Next(); err != io.EOF {
+			t.Errorf("Expected io.EOF, got %v", err)
+		}
+	}
+}
+
+func TestParserAll(t *testing.T) {
+	p := NewParser(strings.NewReader(parserTestContent))
+
+	var titles []string
+	for e, err := range p.All() {
+		if err != nil {
+			t.Fatalf("All() failed: %v", err)
+		}
+		titles = append(titles, e.Title)
+		if len(titles) == 2 {
+			break
+		}
+	}
+
+	if strings.Join(titles, ",") != "Entry 1,Entry 2" {
+		t.Errorf("Expected first 2 entries, got %v", titles)
+	}
+
+	// 途中で止めた場合は続きから読み出せる
+	e, err := p.Next()
+	if err != nil {
+		t.Fatalf("Next() failed: %v", err)
+	}
+	if e.Title != "Entry 3" {
+		t.Errorf("Expected Title 'Entry 3', got %q", e.Title)
+	}
+}
+
+func TestParseReader(t *testing.T) {
+	entries, err := ParseReader(strings.NewReader(parserTestContent))
+	if err != nil {
+		t.Fatalf("ParseReader failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	if entries[2].Body != "本文3" {
+		t.Errorf("Expected Body '本文3', got %q", entries[2].Body)
+	}
+}
+
+func TestParserSkipsUntitledEntries(t *testing.T) {
+	content := "BASENAME: no-title\n-----\nBODY:\n本文\n-----\n--------\nTITLE: Titled\n-----\n"
+
+	entries, err := ParseReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseReader failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Title != "Titled" {
+		t.Errorf("Expected only titled entry, got %+v", entries)
+	}
+}
+
+func TestParserLongLine(t *testing.T) {
+	// bufio.Scannerの上限(64KB)を大きく超える行を含む本文
+	longLine := `<img src="data:image/png;base64,` + strings.Repeat("QUJD", 1<<20) + `">`
+	content := "TITLE: Long Line\n-----\nBODY:\n前の行\n" + longLine + "\n後の行\n-----\n--------\nTITLE: Next\n-----\nBODY:\n次\n-----\n"
+
+	entries, err := ParseReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseReader failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Body != "前の行\n"+longLine+"\n後の行" {
+		t.Errorf("Expected Body with long line (%d bytes), got %d bytes", len(longLine), len(entries[0].Body))
+	}
+	if entries[1].Title != "Next" {
+		t.Errorf("Expected Title 'Next', got %q", entries[1].Title)
+	}
+}
+
+func TestParserMaxLineSize(t *testing.T) {
+	// MaxLineSizeちょうどの行は読み込み、超える行があれば行番号付きのエラーで中止する
+	content := "TITLE: Limit\n-----\nBODY:\n" + strings.Repeat("a", 20) + "\r\n" + strings.Repeat("b", 21) + "\n-----\n"
+
+	p := NewParser(strings.NewReader(content))
+	p.Filename = "blog.txt"
+	p.MaxLineSize = 20
+	_, err := p.Next()
+	if err == nil {
+		t.Fatal("Expected error for line over MaxLineSize")
+	}
+	var d *Diagnostic
+	if !errors.As(err, &d) || d.Line != 5 {
+		t.Errorf("Expected error at line 5, got %v", err)
+	}
+
+	p = NewParser(strings.NewReader(content))
+	p.MaxLineSize = 21
+	e, err := p.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if e.Body != strings.Repeat("a", 20)+"\n"+strings.Repeat("b", 21) {
+		t.Errorf("Unexpected Body %q", e.Body)
+	}
+}
+
+func TestParserLineEndings(t *testing.T) {
+	// CRLF改行と改行で終わらない最終行
+	content := "TITLE: CRLF\r\n-----\r\nBODY:\r\n1行目\r\n2行目\r\n-----"
+
+	entries, err := ParseReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseReader failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Title != "CRLF" {
+		t.Errorf("Expected Title 'CRLF', got %q", entries[0].Title)
+	}
+	if entries[0].Body != "1行目\n2行目" {
+		t.Errorf("Expected Body '1行目\\n2行目', got %q", entries[0].Body)
+	}
+}
+
+const malformedTestContent = `TITLE: Unclosed
+-----
+BODY:
+閉じられていない本文
+--------
+AUTHOR: no_title
+-----
+BODY:
+タイトルなし
+-----
+--------
+TITLE: Unknown Key
+CUSTOM FIELD: value
+ALLOW COMMENTS: 1
+this line has no key
+-----
+BODY:
+本文
+-----
+--------
+`
+
+func TestParserDiagnostics(t *testing.T) {
+	p := NewParser(strings.NewReader(malformedTestContent))
+	p.Filename = "broken.txt"
+
+	var titles []string
+	for e, err := range p.All() {
+		if err != nil {
+			t.Fatalf("All() failed: %v", err)
+		}
+		titles = append(titles, e.Title)
+	}
+
+	// 問題があっても読めるエントリーは返される
+	if strings.Join(titles, ",") != "Unclosed,Unknown Key" {
+		t.Errorf("Expected titled entries, got %v", titles)
+	}
+
+	expected := []Diagnostic{
+		{File: "broken.txt", Line: 3, Entry: 1, Message: "BODY セクションが ----- で閉じられていません"},
+		{File: "broken.txt", Line: 11, Entry: 2, Message: "TITLE のないエントリーを読み飛ばしました"},
+		{File: "broken.txt", Line: 13, Entry: 3, Message: "未知のキーです: CUSTOM FIELD"},
+		{File: "broken.txt", Line: 15, Entry: 3, Message: `解釈できない行です: "this line has no key"`},
+	}
+	diagnostics := p.Diagnostics()
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("Expected %d diagnostics, got %d: %v", len(expected), len(diagnostics), diagnostics)
+	}
+	for i := range expected {
+		if diagnostics[i] != expected[i] {
+			t.Errorf("Diagnostic[%d] = %+v, want %+v", i, diagnostics[i], expected[i])
+		}
+	}
+}
+
+func TestParserStrict(t *testing.T) {
+	p := NewParser(strings.NewReader(malformedTestContent))
+	p.Filename = "broken.txt"
+	p.Strict = true
+
+	_, err := p.Next()
+	if err == nil {
+		t.Fatal("Expected error in strict mode, got nil")
+	}
+	if err.Error() != "broken.txt:3: エントリー1: BODY セクションが ----- で閉じられていません" {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+
+	// エラー後は解析を続けない
+	if _, err := p.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF after error, got %v", err)
+	}
+}
+
+func TestParserNoDiagnosticsForValidInput(t *testing.T) {
+	p := NewParser(strings.NewReader(parserTestContent + "\n\n"))
+	p.Strict = true
+
+	for _, err := range p.All() {
+		if err != nil {
+			t.Fatalf("All() failed: %v", err)
+		}
+	}
+	if len(p.Diagnostics()) != 0 {
+		t.Errorf("Expected no diagnostics, got %v", p.Diagnostics())
+	}
+}
+
+func TestParserDate(t *testing.T) {
+	content := "TITLE: Afternoon\nDATE: 01/15/2023 03:00:00 PM\n-----\n--------\nTITLE: Broken Date\nDATE: 2023-01-15\n-----\n"
+
+	p := NewParser(strings.NewReader(content))
+	p.Location = time.FixedZone("JST", 9*60*60)
+
+	entries, err := collectEntries(p)
+	if err != nil {
+		t.Fatalf("collectEntries failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	expected := time.Date(2023, 1, 15, 15, 0, 0, 0, p.Location)
+	if !entries[0].DateTime.Equal(expected) {
+		t.Errorf("Expected DateTime %v, got %v", expected, entries[0].DateTime)
+	}
+	if entries[0].Date != "01/15/2023 03:00:00 PM" {
+		t.Errorf("Expected raw Date to be kept, got %q", entries[0].Date)
+	}
+
+	// 解釈できない日付は診断として報告され、DateTimeはゼロ値のまま
+	if !entries[1].DateTime.IsZero() {
+		t.Errorf("Expected zero DateTime, got %v", entries[1].DateTime)
+	}
+	diagnostics := p.Diagnostics()
+	if len(diagnostics) != 1 || diagnostics[0].Line != 6 || diagnostics[0].Entry != 2 {
+		t.Errorf("Expected 1 diagnostic for invalid date, got %v", diagnostics)
+	}
+}
+
+func TestParserExtraFields(t *testing.T) {
+	content := "TITLE: Extra\nX-CUSTOM: カスタム値\nNO ENTRY: 0\n-----\n"
+
+	entries, err := ParseReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseReader failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+
+	expected := []Field{
+		{Key: "X-CUSTOM", Value: "カスタム値", Position: 2},
+		{Key: "NO ENTRY", Value: "0", Position: 3},
+	}
+	if !reflect.DeepEqual(entries[0].Extra, expected) {
+		t.Errorf("Expected Extra %v, got %v", expected, entries[0].Extra)
+	}
+
+	if value, ok := entries[0].ExtraValue("X-CUSTOM"); !ok || value != "カスタム値" {
+		t.Errorf("ExtraValue(X-CUSTOM) = %q, %v", value, ok)
+	}
+	if _, ok := entries[0].ExtraValue("MISSING"); ok {
+		t.Error("ExtraValue(MISSING) should not be found")
+	}
+}
+
+func TestParserTypedFlags(t *testing.T) {
+	content := `TITLE: Typed
+STATUS: Draft
+ALLOW COMMENTS: 1
+ALLOW PINGS: 0
+CONVERT BREAKS: markdown
+-----
+--------
+TITLE: Invalid
+STATUS: Hidden
+ALLOW COMMENTS: yes
+CONVERT BREAKS: unknown
+-----
+`
+
+	p := NewParser(strings.NewReader(content))
+	entries, err := collectEntries(p)
+	if err != nil {
+		t.Fatalf("collectEntries failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	typed := entries[0]
+	if typed.Status != StatusDraft {
+		t.Errorf("Expected Status Draft, got %q", typed.Status)
+	}
+	if typed.AllowComments != FlagOn || !typed.AllowComments.Bool() {
+		t.Errorf("Expected AllowComments on, got %v", typed.AllowComments)
+	}
+	if typed.AllowPings != FlagOff || typed.AllowPings.Bool() {
+		t.Errorf("Expected AllowPings off, got %v", typed.AllowPings)
+	}
+	if typed.ConvertBreaks != ConvertBreaksMarkdown {
+		t.Errorf("Expected ConvertBreaks markdown, got %q", typed.ConvertBreaks)
+	}
+	if len(typed.Extra) != 0 {
+		t.Errorf("Expected no Extra, got %v", typed.Extra)
+	}
+
+	// 不正な値は診断として報告され、ALLOW COMMENTSは失われないようExtraに残る
+	invalid := entries[1]
+	if invalid.AllowComments != FlagUnset {
+		t.Errorf("Expected AllowComments unset, got %v", invalid.AllowComments)
+	}
+	if !reflect.DeepEqual(invalid.Extra, []Field{{Key: "ALLOW COMMENTS", Value: "yes", Position: 3}}) {
+		t.Errorf("Expected invalid ALLOW COMMENTS in Extra, got %v", invalid.Extra)
+	}
+	if len(p.Diagnostics()) != 3 {
+		t.Errorf("Expected 3 diagnostics, got %v", p.Diagnostics())
+	}
+}
+
+func TestParserSeparatorsInBody(t *testing.T) {
+	// 本文中の水平線「-----」やASCIIの区切り線「--------」は構造として扱わない
+	content := `TITLE: Horizontal Rule
+CONVERT BREAKS: markdown
+-----
+BODY:
+前半です。
+-----
+後半です。
+--------
+区切り線の後です。
+-----
+--------
+これも本文です。
+--------
+Q: 質問です
+A: 回答です
+-----
+EXTENDED BODY:
+追記です。
+-----
+--------
+TITLE: Next Entry
+-----
+BODY:
+次のエントリーです。
+-----
+--------
+`
+
+	p := NewParser(strings.NewReader(content))
+	entries, err := collectEntries(p)
+	if err != nil {
+		t.Fatalf("collectEntries failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	expectedBody := "前半です。\n-----\n後半です。\n--------\n区切り線の後です。\n-----\n--------\nこれも本文です。\n--------\nQ: 質問です\nA: 回答です"
+	if entries[0].Body != expectedBody {
+		t.Errorf("Expected Body %q, got %q", expectedBody, entries[0].Body)
+	}
+	if entries[0].ExtendedBody != "追記です。" {
+		t.Errorf("Expected ExtendedBody '追記です。', got %q", entries[0].ExtendedBody)
+	}
+	if entries[1].Title != "Next Entry" || entries[1].Body != "次のエントリーです。" {
+		t.Errorf("Unexpected second entry: %+v", entries[1])
+	}
+	if len(p.Diagnostics()) != 0 {
+		t.Errorf("Expected no diagnostics, got %v", p.Diagnostics())
+	}
+}
+
+func TestParserUnclosedSectionBeforeNextEntry(t *testing.T) {
+	// 閉じられていないセクションの後の「--------」は次の行がヘッダーであれば区切りとみなす
+	content := "TITLE: Unclosed\n-----\nBODY:\n本文\n--------\nTITLE: Next\n-----\nBODY:\n次\n-----\n"
+
+	p := NewParser(strings.NewReader(content))
+	entries, err := collectEntries(p)
+	if err != nil {
+		t.Fatalf("collectEntries failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Body != "本文" {
+		t.Errorf("Expected Body '本文', got %q", entries[0].Body)
+	}
+	if len(p.Diagnostics()) != 1 {
+		t.Errorf("Expected 1 diagnostic, got %v", p.Diagnostics())
+	}
+}
+
+func TestIsHeaderLine(t *testing.T) {
+	tests := map[string]bool{
+		"TITLE: タイトル":               true,
+		"ALLOW COMMENTS: 1":         true,
+		"CATEGORY:":                 true,
+		"X-CUSTOM_KEY: value":       false,
+		"EMAIL:":                    false,
+		"Q: 質問です":                   false,
+		"NOTE: 注意":                  false,
+		"URL: https://example.com/": false,
+		"本文: コロンを含む行":               false,
+		"Title: 小文字を含む":             false,
+		"no colon":                  false,
+		": empty key":               false,
+	}
+
+	for line, expected := range tests {
+		if result := isHeaderLine(line); result != expected {
+			t.Errorf("isHeaderLine(%q) = %v, want %v", line, result, expected)
+		}
+	}
+}
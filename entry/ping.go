@@ -0,0 +1,35 @@
+package entry
+
+// Ping エントリーに届いたトラックバックを表現する構造体
+type Ping struct {
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	IP       string `json:"ip,omitempty"`
+	BlogName string `json:"blog_name,omitempty"`
+	Date     string `json:"date,omitempty"`
+	Excerpt  string `json:"excerpt,omitempty"`
+}
+
+// parsePing PINGセクションの行からトラックバックを組み立てる
+func parsePing(lines []string) Ping {
+	var p Ping
+	p.Excerpt = parseMetaLines(lines, p.metaField)
+	return p
+}
+
+// metaField メタデータのキーに対応するフィールドを返す（未知のキーはnil）
+func (p *Ping) metaField(key string) *string {
+	switch key {
+	case "TITLE":
+		return &p.Title
+	case "URL":
+		return &p.URL
+	case "IP":
+		return &p.IP
+	case "BLOG NAME":
+		return &p.BlogName
+	case "DATE":
+		return &p.Date
+	}
+	return nil
+}
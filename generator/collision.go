@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Collision 既に使われていたファイル名と、代わりに割り当てたファイル名
+type Collision struct {
+	Name     string // 生成されたファイル名
+	Existing string // 先に同じ名前で出力されたファイル名
+	Resolved string // 連番を付けて割り当てたファイル名
+}
+
+// NameRegistry 実行全体で出力したファイル名を記録し、衝突したファイル名に連番を付ける
+// 大文字・小文字を区別しないファイルシステムを考慮し、大文字・小文字の違いだけの名前も衝突とみなす
+type NameRegistry struct {
+	used       map[string]string
+	collisions []Collision
+}
+
+// NewNameRegistry 空のNameRegistryを作成
+func NewNameRegistry() *NameRegistry {
+	return &NameRegistry{used: map[string]string{}}
+}
+
+// Reserve ファイル名を登録して返す
+// 既に使われている場合は拡張子の前に「_2」「_3」…と空いている最小の連番を付ける
+func (r *NameRegistry) Reserve(name string) string {
+	existing, ok := r.used[nameKey(name)]
+	if !ok {
+		r.used[nameKey(name)] = name
+		return name
+	}
+
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	resolved := name
+	for n := 2; ; n++ {
+		resolved = fmt.Sprintf("%s_%d%s", base, n, ext)
+		if _, ok := r.used[nameKey(resolved)]; !ok {
+			break
+		}
+	}
+
+	r.used[nameKey(resolved)] = resolved
+	r.collisions = append(r.collisions, Collision{Name: name, Existing: existing, Resolved: resolved})
+	return resolved
+}
+
+// Collisions これまでに見つかった衝突を発生順に返す
+func (r *NameRegistry) Collisions() []Collision {
+	return r.collisions
+}
+
+// nameKey 衝突判定に使うキー
+func nameKey(name string) string {
+	return strings.ToLower(name)
+}
@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"mttohmd/entry"
+)
+
+func TestNameRegistry(t *testing.T) {
+	r := NewNameRegistry()
+
+	names := []string{
+		"2023-01-15_Post.md",
+		"2023-01-15_Post.md",
+		"2023-01-15_Post_2.md", // 連番で割り当て済みの名前と衝突
+		"2023-01-15_post.md",   // 大文字・小文字のみ異なる
+		"2023/01/Slug.md",
+		"2023/01/Slug.md",
+		"Other.md",
+	}
+	var resolved []string
+	for _, name := range names {
+		resolved = append(resolved, r.Reserve(name))
+	}
+
+	expected := []string{
+		"2023-01-15_Post.md",
+		"2023-01-15_Post_2.md",
+		"2023-01-15_Post_2_2.md",
+		"2023-01-15_post_3.md",
+		"2023/01/Slug.md",
+		"2023/01/Slug_2.md",
+		"Other.md",
+	}
+	if !reflect.DeepEqual(resolved, expected) {
+		t.Errorf("Reserve() = %v, want %v", resolved, expected)
+	}
+
+	expectedCollisions := []Collision{
+		{Name: "2023-01-15_Post.md", Existing: "2023-01-15_Post.md", Resolved: "2023-01-15_Post_2.md"},
+		{Name: "2023-01-15_Post_2.md", Existing: "2023-01-15_Post_2.md", Resolved: "2023-01-15_Post_2_2.md"},
+		{Name: "2023-01-15_post.md", Existing: "2023-01-15_Post.md", Resolved: "2023-01-15_post_3.md"},
+		{Name: "2023/01/Slug.md", Existing: "2023/01/Slug.md", Resolved: "2023/01/Slug_2.md"},
+	}
+	if !reflect.DeepEqual(r.Collisions(), expectedCollisions) {
+		t.Errorf("Collisions() = %v, want %v", r.Collisions(), expectedCollisions)
+	}
+}
+
+func TestNameRegistrySanitizedTitles(t *testing.T) {
+	// 置き換えられる文字だけが異なるタイトルも同じファイル名になり衝突する
+	r := NewNameRegistry()
+	first := r.Reserve(GenerateFilename(entry.Entry{Title: "A/B"}))
+	second := r.Reserve(GenerateFilename(entry.Entry{Title: "A:B"}))
+
+	if first != "A_B.md" || second != "A_B_2.md" {
+		t.Errorf("Expected A_B.md and A_B_2.md, got %q and %q", first, second)
+	}
+	if len(r.Collisions()) != 1 {
+		t.Errorf("Expected 1 collision, got %d", len(r.Collisions()))
+	}
+}
@@ -3,6 +3,7 @@ package generator
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"mttohmd/entry"
 )
@@ -43,19 +44,28 @@ func TestGenerateFilename(t *testing.T) {
 			expected: "2023-01-15-blog-post_Blog_Post.md",
 		},
 		{
-			name: "DATE形式から日付抽出",
+			name: "DATEから日付抽出",
 			entry: entry.Entry{
-				Title: "Another Post",
-				Date:  "01/15/2023 14:30:45 PM",
+				Title:    "Another Post",
+				Date:     "01/15/2023 02:30:45 PM",
+				DateTime: time.Date(2023, 1, 15, 14, 30, 45, 0, time.UTC),
 			},
 			expected: "2023-01-15-143045_Another_Post.md",
 		},
+		{
+			name: "未解析のDATEのみ",
+			entry: entry.Entry{
+				Title: "Raw Date",
+				Date:  "01/15/2023 02:30:45 PM",
+			},
+			expected: "Raw_Date.md",
+		},
 		{
 			name: "BasenameとDATEの両方がある場合（Basenameが優先）",
 			entry: entry.Entry{
 				Title:    "Priority Test",
 				Basename: "2023/01/15/priority",
-				Date:     "01/16/2023 10:00:00 AM",
+				DateTime: time.Date(2023, 1, 16, 10, 0, 0, 0, time.UTC),
 			},
 			expected: "2023-01-15-priority_Priority_Test.md",
 		},
@@ -64,7 +74,7 @@ func TestGenerateFilename(t *testing.T) {
 			entry: entry.Entry{
 				Title:    "No Date Format",
 				Basename: "simple-basename",
-				Date:     "01/15/2023 12:00:00 PM",
+				DateTime: time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC),
 			},
 			expected: "2023-01-15-120000_No_Date_Format.md",
 		},
@@ -97,14 +107,14 @@ func TestGenerateFilename(t *testing.T) {
 func TestGenerateMTContent(t *testing.T) {
 	// 全フィールドを含むテスト
 	fullEntry := entry.Entry{
-		Author:   "test_author",
-		Title:    "Test Entry",
-		Basename: "test_entry",
-		Status:   "Publish",
-		Date:     "01/15/2023 12:00:00 AM",
-		Category: "Technology",
-		Body:     "これはテスト用の本文です。\n複数行のテストです。",
-		ImageURL: "https://example.com/image.jpg",
+		Author:     "test_author",
+		Title:      "Test Entry",
+		Basename:   "test_entry",
+		Status:     "Publish",
+		Date:       "01/15/2023 12:00:00 AM",
+		Categories: []entry.Category{{Name: "Technology"}},
+		Body:       "これはテスト用の本文です。\n複数行のテストです。",
+		ImageURL:   "https://example.com/image.jpg",
 	}
 
 	result := GenerateMTContent(fullEntry)
@@ -201,9 +211,9 @@ func TestGenerateMTContentEmpty(t *testing.T) {
 func TestGenerateMTContentSpecialCharacters(t *testing.T) {
 	// 特殊文字を含むテスト
 	specialEntry := entry.Entry{
-		Title:    "Special: Characters & Symbols <test>",
-		Body:     "Body with\nnewlines and\ttabs",
-		Category: "Test, Special Characters",
+		Title:      "Special: Characters & Symbols <test>",
+		Body:       "Body with\nnewlines and\ttabs",
+		Categories: []entry.Category{{Name: "Test, Special Characters"}},
 	}
 
 	result := GenerateMTContent(specialEntry)
@@ -219,3 +229,224 @@ func TestGenerateMTContentSpecialCharacters(t *testing.T) {
 		t.Error("Newlines and tabs in body should be preserved")
 	}
 }
+
+func TestGenerateMTContentSections(t *testing.T) {
+	// 追記・概要・キーワードを含むテスト
+	sectionEntry := entry.Entry{
+		Title:        "Section Entry",
+		Body:         "本文",
+		ExtendedBody: "追記",
+		Excerpt:      "概要",
+		Keywords:     "go, mt",
+	}
+
+	result := GenerateMTContent(sectionEntry)
+
+	expected := "BODY:\n本文\n-----\nEXTENDED BODY:\n追記\n-----\nEXCERPT:\n概要\n-----\nKEYWORDS:\ngo, mt\n-----\n"
+	if !strings.HasSuffix(result, expected) {
+		t.Errorf("GenerateMTContent() = %q, want suffix %q", result, expected)
+	}
+}
+
+func TestGenerateMTContentComments(t *testing.T) {
+	commentEntry := entry.Entry{
+		Title: "Comment Entry",
+		Body:  "本文",
+		Comments: []entry.Comment{
+			{
+				Author: "commenter",
+				Email:  "commenter@example.com",
+				IP:     "192.0.2.1",
+				URL:    "https://example.com/",
+				Date:   "01/02/2023 10:00:00 AM",
+				Body:   "コメント本文",
+			},
+			{
+				Author: "another",
+				Body:   "2つ目",
+			},
+		},
+	}
+
+	result := GenerateMTContent(commentEntry)
+
+	expected := "BODY:\n本文\n-----\n" +
+		"COMMENT:\nAUTHOR: commenter\nEMAIL: commenter@example.com\nIP: 192.0.2.1\nURL: https://example.com/\nDATE: 01/02/2023 10:00:00 AM\nコメント本文\n-----\n" +
+		"COMMENT:\nAUTHOR: another\n2つ目\n-----\n"
+	if !strings.HasSuffix(result, expected) {
+		t.Errorf("GenerateMTContent() = %q, want suffix %q", result, expected)
+	}
+}
+
+func TestGenerateMTContentPings(t *testing.T) {
+	pingEntry := entry.Entry{
+		Title: "Ping Entry",
+		Body:  "本文",
+		Pings: []entry.Ping{
+			{
+				Title:    "言及した記事",
+				URL:      "https://example.com/entry/1",
+				BlogName: "Example Blog",
+				Date:     "01/04/2023 09:00:00 AM",
+				Excerpt:  "抜粋",
+			},
+		},
+	}
+
+	result := GenerateMTContent(pingEntry)
+
+	expected := "BODY:\n本文\n-----\n" +
+		"PING:\nTITLE: 言及した記事\nURL: https://example.com/entry/1\nBLOG NAME: Example Blog\nDATE: 01/04/2023 09:00:00 AM\n抜粋\n-----\n"
+	if !strings.HasSuffix(result, expected) {
+		t.Errorf("GenerateMTContent() = %q, want suffix %q", result, expected)
+	}
+}
+
+func TestGenerateMTContentCategories(t *testing.T) {
+	categoryEntry := entry.Entry{
+		Title: "Category Entry",
+		Categories: []entry.Category{
+			{Name: "Go", Primary: true},
+			{Name: "プログラミング"},
+		},
+	}
+
+	result := GenerateMTContent(categoryEntry)
+
+	expected := "TITLE: Category Entry\nPRIMARY CATEGORY: Go\nCATEGORY: Go\nCATEGORY: プログラミング\n-----\n"
+	if !strings.HasPrefix(result, expected) {
+		t.Errorf("GenerateMTContent() = %q, want prefix %q", result, expected)
+	}
+
+	// 主カテゴリーがない場合はPRIMARY CATEGORYを出力しない
+	categoryEntry.Categories[0].Primary = false
+	if strings.Contains(GenerateMTContent(categoryEntry), "PRIMARY CATEGORY:") {
+		t.Error("PRIMARY CATEGORY should not be present")
+	}
+}
+
+func TestGenerateMTContentDateTime(t *testing.T) {
+	// 元の表記がない場合は解析済みの日時から出力
+	dateEntry := entry.Entry{
+		Title:    "Date Entry",
+		DateTime: time.Date(2023, 1, 15, 15, 4, 5, 0, time.UTC),
+	}
+
+	result := GenerateMTContent(dateEntry)
+	if !strings.Contains(result, "DATE: 01/15/2023 03:04:05 PM\n") {
+		t.Errorf("Expected formatted DATE, got %q", result)
+	}
+
+	// 元の表記がある場合はそのまま出力
+	dateEntry.Date = "01/15/2023 15:04:05"
+	result = GenerateMTContent(dateEntry)
+	if !strings.Contains(result, "DATE: 01/15/2023 15:04:05\n") {
+		t.Errorf("Expected raw DATE, got %q", result)
+	}
+}
+
+func TestGenerateMTContentExtra(t *testing.T) {
+	extraEntry := entry.Entry{
+		Title:         "Extra Entry",
+		Status:        entry.StatusPublish,
+		Date:          "01/15/2023 15:00:00",
+		AllowComments: entry.FlagOn,
+		AllowPings:    entry.FlagOff,
+		ConvertBreaks: entry.ConvertBreaksNone,
+		Extra: []entry.Field{
+			{Key: "X-CUSTOM", Value: "value"},
+		},
+	}
+
+	result := GenerateMTContent(extraEntry)
+
+	expected := "TITLE: Extra Entry\nSTATUS: Publish\nALLOW COMMENTS: 1\nALLOW PINGS: 0\nCONVERT BREAKS: 0\nX-CUSTOM: value\nDATE: 01/15/2023 15:00:00\n"
+	if !strings.HasPrefix(result, expected) {
+		t.Errorf("GenerateMTContent() = %q, want prefix %q", result, expected)
+	}
+}
+
+func TestGenerateMTContentRoundTrip(t *testing.T) {
+	// はてなブログのエクスポートと同じ並びの入力は、解析→生成でバイト単位まで元に戻る
+	input := `AUTHOR: basyura
+TITLE: Round Trip
+BASENAME: 2023/01/15/150000
+STATUS: Publish
+ALLOW COMMENTS: 1
+CONVERT BREAKS: 0
+X-CUSTOM: custom value
+DATE: 01/15/2023 15:00:00
+CATEGORY: Go
+CATEGORY: テスト
+IMAGE: https://example.com/image.jpg
+-----
+BODY:
+<p>本文です。</p>
+
+<p>2段落目です。</p>
+-----
+EXTENDED BODY:
+<p>追記です。</p>
+-----
+EXCERPT:
+概要です。
+-----
+COMMENT:
+AUTHOR: commenter
+URL: https://example.com/
+DATE: 01/16/2023 10:00:00
+コメントです。
+-----
+PING:
+TITLE: 言及した記事
+URL: https://example.com/entry/1
+BLOG NAME: Example Blog
+DATE: 01/17/2023 09:00:00
+抜粋です。
+-----
+`
+
+	entries, err := entry.ParseReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseReader failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+
+	if result := GenerateMTContent(entries[0]); result != input {
+		t.Errorf("Round trip mismatch:\ngot:\n%s\nwant:\n%s", result, input)
+	}
+}
+
+func TestGenerateMTContentRoundTripExtraPosition(t *testing.T) {
+	// 解析対象外のヘッダーはヘッダーの先頭・途中・末尾のどこにあっても元の位置に戻る
+	input := `X-FIRST: 先頭
+TITLE: Extra Position
+STATUS: Publish
+ALLOW COMMENTS: yes
+DATE: 01/15/2023 15:00:00
+NO ENTRY: 0
+CATEGORY: Go
+TAGS: a, b
+CATEGORY: テスト
+IMAGE: https://example.com/image.jpg
+X-LAST: 末尾
+-----
+BODY:
+本文
+-----
+`
+
+	entries, err := entry.ParseReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseReader failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+
+	if result := GenerateMTContent(entries[0]); result != input {
+		t.Errorf("Round trip mismatch:\ngot:\n%s\nwant:\n%s", result, input)
+	}
+}
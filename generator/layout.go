@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+
+	"mttohmd/entry"
+)
+
+// Layout 出力ファイルをどのようなディレクトリ構成で置くか
+type Layout int
+
+const (
+	// LayoutFlat 出力ディレクトリの直下に全てのファイルを置く
+	LayoutFlat Layout = iota
+	// LayoutYear 年ごとのディレクトリ（2023/）に置く
+	LayoutYear
+	// LayoutYearMonth 年・月ごとのディレクトリ（2023/01/）に置く
+	LayoutYearMonth
+	// LayoutCategory 主カテゴリーごとのディレクトリに置く
+	LayoutCategory
+)
+
+// 日時やカテゴリーがないエントリーを置くディレクトリ
+const (
+	undatedDir       = "undated"
+	uncategorizedDir = "uncategorized"
+)
+
+// ParseLayout 名前からLayoutを返す
+func ParseLayout(name string) (Layout, error) {
+	switch name {
+	case "flat":
+		return LayoutFlat, nil
+	case "year":
+		return LayoutYear, nil
+	case "year-month":
+		return LayoutYearMonth, nil
+	case "category":
+		return LayoutCategory, nil
+	}
+	return LayoutFlat, fmt.Errorf("未対応のディレクトリ構成です: %s", name)
+}
+
+// String ディレクトリ構成の名前を返す
+func (l Layout) String() string {
+	switch l {
+	case LayoutFlat:
+		return "flat"
+	case LayoutYear:
+		return "year"
+	case LayoutYearMonth:
+		return "year-month"
+	case LayoutCategory:
+		return "category"
+	}
+	return fmt.Sprintf("Layout(%d)", int(l))
+}
+
+// Dir エントリーを置くディレクトリを出力ディレクトリからの相対パス（「/」区切り）で返す
+// 日時のないエントリーはundated、カテゴリーのないエントリーはuncategorizedに置く
+func (l Layout) Dir(e entry.Entry, p Profile) string {
+	switch l {
+	case LayoutYear, LayoutYearMonth:
+		t, ok := entryTime(e)
+		if !ok {
+			return undatedDir
+		}
+		if l == LayoutYear {
+			return t.Format("2006")
+		}
+		return t.Format("2006/01")
+	case LayoutCategory:
+		if category := e.PrimaryCategory(); category != "" {
+			return SanitizeName(category, p)
+		}
+		return uncategorizedDir
+	}
+	return ""
+}
+
+// Path エントリーのファイル名をディレクトリ構成に従った相対パス（「/」区切り）にする
+func (l Layout) Path(e entry.Entry, filename string, p Profile) string {
+	return path.Join(l.Dir(e, p), filename)
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"mttohmd/entry"
+)
+
+func TestLayoutPath(t *testing.T) {
+	dated := entry.Entry{
+		Title:      "Post",
+		DateTime:   time.Date(2023, 1, 15, 14, 30, 45, 0, time.UTC),
+		Categories: []entry.Category{{Name: "Go"}, {Name: "C# / .NET", Primary: true}},
+	}
+	hatena := entry.Entry{Title: "Post", Basename: "2022/12/31/235959"}
+	bare := entry.Entry{Title: "Post"}
+
+	tests := []struct {
+		name     string
+		layout   Layout
+		entry    entry.Entry
+		expected string
+	}{
+		{name: "フラット", layout: LayoutFlat, entry: dated, expected: "Post.md"},
+		{name: "年", layout: LayoutYear, entry: dated, expected: "2023/Post.md"},
+		{name: "年月", layout: LayoutYearMonth, entry: dated, expected: "2023/01/Post.md"},
+		{name: "Basenameの日付", layout: LayoutYearMonth, entry: hatena, expected: "2022/12/Post.md"},
+		{name: "日時なし", layout: LayoutYear, entry: bare, expected: "undated/Post.md"},
+		{name: "主カテゴリー", layout: LayoutCategory, entry: dated, expected: "C#___.NET/Post.md"},
+		{name: "カテゴリーなし", layout: LayoutCategory, entry: bare, expected: "uncategorized/Post.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.layout.Path(tt.entry, "Post.md", ProfilePortable); result != tt.expected {
+				t.Errorf("Path() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	for _, l := range []Layout{LayoutFlat, LayoutYear, LayoutYearMonth, LayoutCategory} {
+		parsed, err := ParseLayout(l.String())
+		if err != nil || parsed != l {
+			t.Errorf("ParseLayout(%q) = %v, %v", l.String(), parsed, err)
+		}
+	}
+	if _, err := ParseLayout("unknown"); err == nil {
+		t.Error("Expected error for unknown layout")
+	}
+}
@@ -0,0 +1,169 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"mttohmd/nfc"
+)
+
+// Profile ファイル名に使えない文字などをどこまで厳しく置き換えるか
+type Profile int
+
+const (
+	// ProfilePortable Windows・macOS・Linuxのいずれでも扱える名前にする（既定）
+	ProfilePortable Profile = iota
+	// ProfilePOSIX 「/」と制御文字のみを置き換え、空白などはそのまま残す
+	ProfilePOSIX
+	// ProfileStrict ProfilePortableに加え、シェルやURLで特別な意味を持つ記号も置き換える
+	ProfileStrict
+)
+
+// ParseProfile 名前からProfileを返す
+func ParseProfile(name string) (Profile, error) {
+	switch name {
+	case "portable":
+		return ProfilePortable, nil
+	case "posix":
+		return ProfilePOSIX, nil
+	case "strict":
+		return ProfileStrict, nil
+	}
+	return ProfilePortable, fmt.Errorf("未対応のファイル名の方針です: %s", name)
+}
+
+// String 方針の名前を返す
+func (p Profile) String() string {
+	switch p {
+	case ProfilePortable:
+		return "portable"
+	case ProfilePOSIX:
+		return "posix"
+	case ProfileStrict:
+		return "strict"
+	}
+	return fmt.Sprintf("Profile(%d)", int(p))
+}
+
+// MaxNameBytes 1つのファイル名の最大バイト数（ext4などの上限）
+const MaxNameBytes = 255
+
+// nameReserveBytes 衝突時の連番（_2など）や、拡張子を.trackbacks.jsonに替えたサイドカーファイルのために空けておくバイト数
+const nameReserveBytes = 24
+
+// maxExtBytes 拡張子とみなす最大のバイト数（これより長い「.」以降は名前の一部として切り詰める）
+const maxExtBytes = 16
+
+// windowsReservedNames Windowsで拡張子を付けても使えない名前
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// SanitizePath 「/」で区切った各要素をSanitizeNameで整える
+func SanitizePath(name string, p Profile) string {
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		parts[i] = SanitizeName(part, p)
+	}
+	return strings.Join(parts, "/")
+}
+
+// SanitizeName 1つのファイル名を方針に従って整える
+// NFC正規化・制御文字の除去・使えない文字の置き換え・UTF-8の文字境界での切り詰めを行い、
+// ProfilePOSIX以外では末尾の「.」と空白、先頭の「.」、Windowsの予約名も避ける
+func SanitizeName(name string, p Profile) string {
+	name = truncateName(replaceUnsafe(name, p), MaxNameBytes-nameReserveBytes)
+
+	if p != ProfilePOSIX {
+		name = strings.TrimRight(name, ". ")
+		if strings.HasPrefix(name, ".") {
+			name = "_" + name[1:]
+		}
+
+		stem, rest, _ := strings.Cut(name, ".")
+		if windowsReservedNames[strings.ToUpper(strings.TrimRight(stem, " "))] {
+			name = stem + "_"
+			if rest != "" {
+				name += "." + rest
+			}
+		}
+	}
+
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
+
+// replaceUnsafe NFC正規化し、制御文字を除いて使えない文字を「_」に置き換える
+func replaceUnsafe(s string, p Profile) string {
+	s = nfc.Normalize(s)
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case unicode.IsControl(r), p == ProfileStrict && unicode.Is(unicode.Cf, r):
+			continue
+		case r == '/', p != ProfilePOSIX && isPortableUnsafe(r), p == ProfileStrict && isStrictUnsafe(r):
+			r = '_'
+			// 記号の置き換えで「_」が続く場合は1つにまとめる
+			if p == ProfileStrict && strings.HasSuffix(b.String(), "_") {
+				continue
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// isPortableUnsafe Windowsで使えない文字と空白
+func isPortableUnsafe(r rune) bool {
+	return strings.ContainsRune(`<>:"\|?* `, r)
+}
+
+// isStrictUnsafe シェルやURLで特別な意味を持つ記号と空白文字
+func isStrictUnsafe(r rune) bool {
+	return strings.ContainsRune("#%&{}$!'@+=;,[]^~`", r) || unicode.IsSpace(r)
+}
+
+// truncateName 拡張子を残したまま、名前がmaxバイト以下になるようUTF-8の文字境界で切り詰める
+// 結合文字の直前では切らず、基底文字ごと取り除く
+func truncateName(name string, max int) string {
+	if len(name) <= max {
+		return name
+	}
+
+	ext := path.Ext(name)
+	if len(ext) > maxExtBytes || len(ext) >= max {
+		ext = ""
+	}
+	stem := strings.TrimSuffix(name, ext)
+	limit := max - len(ext)
+
+	cut := 0
+	for i, r := range stem {
+		if i+utf8.RuneLen(r) > limit {
+			break
+		}
+		cut = i + utf8.RuneLen(r)
+	}
+	for cut > 0 {
+		next, _ := utf8.DecodeRuneInString(stem[cut:])
+		if !isCombining(next) {
+			break
+		}
+		_, size := utf8.DecodeLastRuneInString(stem[:cut])
+		cut -= size
+	}
+	return stem[:cut] + ext
+}
+
+// isCombining 直前の文字と組み合わせて表示される文字かどうか
+func isCombining(r rune) bool {
+	return nfc.CombiningClass(r) != 0 || unicode.In(r, unicode.Mn, unicode.Me) || r == 0x200D
+}
@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"mttohmd/entry"
+)
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		profile  Profile
+		expected string
+	}{
+		{name: "変更なし", input: "2023-01-15_Post.md", profile: ProfilePortable, expected: "2023-01-15_Post.md"},
+		{name: "使えない文字と空白", input: `a<b>c:"d|e?f*g h.md`, profile: ProfilePortable, expected: "a_b_c__d_e_f_g_h.md"},
+		{name: "制御文字の除去", input: "tab\there\x7f.md", profile: ProfilePortable, expected: "tabhere.md"},
+		{name: "NFC正規化", input: "\u304B\u3099.md", profile: ProfilePortable, expected: "\u304C.md"},
+		{name: "予約名", input: "CON", profile: ProfilePortable, expected: "CON_"},
+		{name: "拡張子付きの予約名", input: "com1.md", profile: ProfilePortable, expected: "com1_.md"},
+		{name: "予約名に似た名前", input: "CONSOLE.md", profile: ProfilePortable, expected: "CONSOLE.md"},
+		{name: "末尾の「.」", input: "Hello...", profile: ProfilePortable, expected: "Hello"},
+		{name: "先頭の「.」", input: ".htaccess", profile: ProfilePortable, expected: "_htaccess"},
+		{name: "「.」のみ", input: "..", profile: ProfilePortable, expected: "_"},
+		{name: "空", input: "", profile: ProfilePortable, expected: "_"},
+		{name: "POSIXでは空白や記号を残す", input: `a b:c?.md`, profile: ProfilePOSIX, expected: `a b:c?.md`},
+		{name: "POSIXでも「/」は置き換える", input: "a/b.md", profile: ProfilePOSIX, expected: "a_b.md"},
+		{name: "POSIXでは予約名を許す", input: "CON.md", profile: ProfilePOSIX, expected: "CON.md"},
+		{name: "POSIXでも「..」は使わない", input: "..", profile: ProfilePOSIX, expected: "_"},
+		{name: "strictでは記号も置き換える", input: "C# & F#!.md", profile: ProfileStrict, expected: "C_F_.md"},
+		{name: "strictではゼロ幅文字を除去", input: "a\u200Bb.md", profile: ProfileStrict, expected: "ab.md"},
+		{name: "strictでは全角空白も置き換える", input: "日本語\u3000タイトル.md", profile: ProfileStrict, expected: "日本語_タイトル.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := SanitizeName(tt.input, tt.profile); result != tt.expected {
+				t.Errorf("SanitizeName(%q, %v) = %q, want %q", tt.input, tt.profile, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSanitizeNameTruncate(t *testing.T) {
+	limit := MaxNameBytes - nameReserveBytes
+
+	tests := []struct {
+		name  string
+		input string
+		ext   string
+	}{
+		{name: "日本語の長いタイトル", input: strings.Repeat("日本語", 40) + ".md", ext: ".md"},
+		{name: "結合文字を含むタイトル", input: "a" + strings.Repeat("\u304D\u309A", 60) + ".md", ext: ".md"},
+		{name: "長い拡張子は名前の一部として扱う", input: strings.Repeat("a", 200) + "." + strings.Repeat("b", 100), ext: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SanitizeName(tt.input, ProfilePortable)
+			if len(result) > limit {
+				t.Errorf("Expected at most %d bytes, got %d", limit, len(result))
+			}
+			if !utf8.ValidString(result) {
+				t.Errorf("Expected valid UTF-8, got %q", result)
+			}
+			if !strings.HasSuffix(result, tt.ext) {
+				t.Errorf("Expected extension %q to be kept, got %q", tt.ext, result)
+			}
+			if stem := strings.TrimSuffix(result, tt.ext); strings.HasSuffix(stem, "き") {
+				t.Errorf("Expected combining mark not to be separated, got %q", result)
+			}
+		})
+	}
+}
+
+func TestSanitizePath(t *testing.T) {
+	result := SanitizePath("2023/CON/a b.md", ProfilePortable)
+	if result != "2023/CON_/a_b.md" {
+		t.Errorf("SanitizePath() = %q, want %q", result, "2023/CON_/a_b.md")
+	}
+}
+
+func TestGenerateFilenameWithProfile(t *testing.T) {
+	e := entry.Entry{Title: "C# & Go: 入門"}
+
+	tests := map[Profile]string{
+		ProfilePortable: "C#_&_Go__入門.md",
+		ProfilePOSIX:    "C# & Go: 入門.md",
+		ProfileStrict:   "C_Go_入門.md",
+	}
+	for profile, expected := range tests {
+		if result := GenerateFilenameWithProfile(e, profile); result != expected {
+			t.Errorf("GenerateFilenameWithProfile(%v) = %q, want %q", profile, result, expected)
+		}
+	}
+}
+
+func TestParseProfile(t *testing.T) {
+	for _, p := range []Profile{ProfilePortable, ProfilePOSIX, ProfileStrict} {
+		parsed, err := ParseProfile(p.String())
+		if err != nil || parsed != p {
+			t.Errorf("ParseProfile(%q) = %v, %v", p.String(), parsed, err)
+		}
+	}
+	if _, err := ParseProfile("unknown"); err == nil {
+		t.Error("Expected error for unknown profile")
+	}
+}
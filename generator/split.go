@@ -0,0 +1,32 @@
+package generator
+
+import (
+	"strings"
+
+	"mttohmd/entry"
+)
+
+// SplitExport エントリーを、件数がmaxEntries以下かつバイト数がmaxBytes以下のMT形式のエクスポートに分ける
+// 0以下の上限は制限なしとして扱う。エントリーの途中では分けないため、
+// 1件でmaxBytesを超えるエントリーはそのエントリーだけのエクスポートになる
+func SplitExport(entries []entry.Entry, maxEntries, maxBytes int) []string {
+	var chunks []string
+	var chunk strings.Builder
+	count := 0
+
+	for _, e := range entries {
+		content := GenerateMTContent(e) + EntrySeparator + "\n"
+		full := (maxEntries > 0 && count >= maxEntries) || (maxBytes > 0 && chunk.Len()+len(content) > maxBytes)
+		if count > 0 && full {
+			chunks = append(chunks, chunk.String())
+			chunk.Reset()
+			count = 0
+		}
+		chunk.WriteString(content)
+		count++
+	}
+	if count > 0 {
+		chunks = append(chunks, chunk.String())
+	}
+	return chunks
+}
@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"mttohmd/entry"
+)
+
+func TestSplitExport(t *testing.T) {
+	entries := []entry.Entry{
+		{Title: "1", Body: "a"},
+		{Title: "2", Body: "b"},
+		{Title: "3", Body: strings.Repeat("c", 100)},
+		{Title: "4", Body: "d"},
+		{Title: "5", Body: "e"},
+	}
+	size := len(GenerateMTContent(entries[0]) + "--------\n")
+
+	tests := []struct {
+		name       string
+		maxEntries int
+		maxBytes   int
+		expected   [][]string
+	}{
+		{name: "制限なし", expected: [][]string{{"1", "2", "3", "4", "5"}}},
+		{name: "件数", maxEntries: 2, expected: [][]string{{"1", "2"}, {"3", "4"}, {"5"}}},
+		{name: "バイト数（上限を超えるエントリーは単独）", maxBytes: size * 2, expected: [][]string{{"1", "2"}, {"3"}, {"4", "5"}}},
+		{name: "件数とバイト数", maxEntries: 1, maxBytes: size * 10, expected: [][]string{{"1"}, {"2"}, {"3"}, {"4"}, {"5"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := SplitExport(entries, tt.maxEntries, tt.maxBytes)
+
+			// 各エクスポートがそれぞれ正しく解析できること
+			var titles [][]string
+			for _, chunk := range chunks {
+				parsed, err := entry.ParseReader(strings.NewReader(chunk))
+				if err != nil {
+					t.Fatalf("ParseReader failed: %v", err)
+				}
+				var names []string
+				for _, e := range parsed {
+					names = append(names, e.Title)
+				}
+				titles = append(titles, names)
+			}
+			if !reflect.DeepEqual(titles, tt.expected) {
+				t.Errorf("SplitExport() = %v, want %v", titles, tt.expected)
+			}
+		})
+	}
+
+	if chunks := SplitExport(nil, 1, 1); len(chunks) != 0 {
+		t.Errorf("Expected no chunks for no entries, got %d", len(chunks))
+	}
+}
@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"mttohmd/entry"
+	"mttohmd/slug"
+)
+
+// FilenameData ファイル名テンプレートに渡すエントリーの情報
+// 文字列はファイル名に使えない文字を方針に従って「_」に置き換え済み
+type FilenameData struct {
+	Year       string // 4桁の年
+	Month      string // 2桁の月
+	Day        string // 2桁の日
+	Hour       string // 2桁の時（24時間制）
+	Minute     string // 2桁の分
+	Second     string // 2桁の秒
+	Slug       string // Basenameの最後の要素（Basenameがなければタイトル）
+	RomajiSlug string // タイトルをローマ字にしたスラッグ（slug.Generateで生成できなければSlugと同じ）
+	Basename   string // BASENAME（「/」はディレクトリの区切りとして残す）
+	Title      string
+	Category   string // 主カテゴリー
+	Index      int    // 1から始まる出力順の番号
+}
+
+// FilenameTemplate text/templateで書いたファイル名のパターン
+// 例: {{.Year}}/{{.Month}}/{{.Slug}}.md
+type FilenameTemplate struct {
+	// Profile ファイル名に使えない文字などの扱い
+	Profile Profile
+
+	tmpl *template.Template
+}
+
+// ParseFilenameTemplate ファイル名のパターンを解析
+func ParseFilenameTemplate(pattern string) (*FilenameTemplate, error) {
+	tmpl, err := template.New("filename").Parse(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("ファイル名テンプレートを解析できません: %w", err)
+	}
+
+	// 存在しないフィールドなどは出力を始める前に検出する
+	if err := tmpl.Execute(io.Discard, FilenameData{}); err != nil {
+		return nil, fmt.Errorf("ファイル名テンプレートを解析できません: %w", err)
+	}
+	return &FilenameTemplate{tmpl: tmpl}, nil
+}
+
+// Execute エントリーのファイル名を生成（「/」で区切るとサブディレクトリになる）
+func (t *FilenameTemplate) Execute(e entry.Entry, index int) (string, error) {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, NewFilenameData(e, index, t.Profile)); err != nil {
+		return "", fmt.Errorf("ファイル名テンプレートを実行できません: %w", err)
+	}
+
+	// 出力ディレクトリの外を指すパスは生成しない
+	raw := b.String()
+	name := path.Clean(raw)
+	if raw == "" || strings.HasSuffix(raw, "/") || path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+		return "", fmt.Errorf("ファイル名テンプレートが不正なパスを生成しました: %q", raw)
+	}
+	return SanitizePath(name, t.Profile), nil
+}
+
+// NewFilenameData エントリーからテンプレートに渡す情報を作成
+func NewFilenameData(e entry.Entry, index int, p Profile) FilenameData {
+	data := FilenameData{
+		Title:      replaceUnsafe(e.Title, p),
+		Category:   replaceUnsafe(e.PrimaryCategory(), p),
+		RomajiSlug: slug.Generate(e),
+		Index:      index,
+	}
+
+	segments := strings.Split(e.Basename, "/")
+	for i, s := range segments {
+		segments[i] = replaceUnsafe(s, p)
+	}
+	data.Basename = strings.Join(segments, "/")
+	data.Slug = segments[len(segments)-1]
+	if data.Slug == "" {
+		data.Slug = data.Title
+	}
+	if data.RomajiSlug == "" {
+		data.RomajiSlug = data.Slug
+	}
+
+	if t, ok := entryTime(e); ok {
+		data.Year = t.Format("2006")
+		data.Month = t.Format("01")
+		data.Day = t.Format("02")
+		data.Hour = t.Format("15")
+		data.Minute = t.Format("04")
+		data.Second = t.Format("05")
+	}
+	return data
+}
+
+// entryTime エントリーの日時を返す（DATEがなければ日付形式のBasenameから推測）
+func entryTime(e entry.Entry) (time.Time, bool) {
+	if !e.DateTime.IsZero() {
+		return e.DateTime, true
+	}
+	if !datedBasename.MatchString(e.Basename) {
+		return time.Time{}, false
+	}
+
+	// はてなブログのBasenameは「YYYY/MM/DD/hhmmss」形式
+	if t, err := time.Parse("2006/01/02/150405", e.Basename); err == nil {
+		return t, true
+	}
+	t, err := time.Parse("2006/01/02", e.Basename[:len("2006/01/02")])
+	return t, err == nil
+}
@@ -0,0 +1,145 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"mttohmd/entry"
+)
+
+func TestFilenameTemplate(t *testing.T) {
+	hatena := entry.Entry{
+		Title:      "テスト エントリー",
+		Basename:   "2023/01/15/123456",
+		DateTime:   time.Date(2023, 1, 15, 14, 30, 45, 0, time.UTC),
+		Categories: []entry.Category{{Name: "Go"}, {Name: "日記", Primary: true}},
+	}
+
+	tests := []struct {
+		name     string
+		pattern  string
+		entry    entry.Entry
+		expected string
+	}{
+		{
+			name:     "年月のディレクトリとBasename",
+			pattern:  "{{.Year}}/{{.Month}}/{{.Basename}}.md",
+			entry:    hatena,
+			expected: "2023/01/2023/01/15/123456.md",
+		},
+		{
+			name:     "スラッグ",
+			pattern:  "{{.Slug}}.md",
+			entry:    hatena,
+			expected: "123456.md",
+		},
+		{
+			name:     "日時の各要素",
+			pattern:  "{{.Year}}-{{.Month}}-{{.Day}}-{{.Hour}}{{.Minute}}{{.Second}}.md",
+			entry:    hatena,
+			expected: "2023-01-15-143045.md",
+		},
+		{
+			name:     "カテゴリーとタイトル",
+			pattern:  "{{.Category}}/{{.Title}}.md",
+			entry:    hatena,
+			expected: "日記/テスト_エントリー.md",
+		},
+		{
+			name:     "ローマ字のスラッグ",
+			pattern:  "{{.Year}}/{{.RomajiSlug}}.md",
+			entry:    entry.Entry{Title: "はじめてのGo", DateTime: time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)},
+			expected: "2023/hajimeteno-go.md",
+		},
+		{
+			name:     "ローマ字にできないタイトルのスラッグ",
+			pattern:  "{{.RomajiSlug}}.md",
+			entry:    entry.Entry{Title: "日本語"},
+			expected: "日本語.md",
+		},
+		{
+			name:     "連番",
+			pattern:  `{{printf "%04d" .Index}}_{{.Slug}}.md`,
+			entry:    hatena,
+			expected: "0007_123456.md",
+		},
+		{
+			name:     "Basenameがない場合のスラッグはタイトル",
+			pattern:  "{{.Slug}}.md",
+			entry:    entry.Entry{Title: "No Basename"},
+			expected: "No_Basename.md",
+		},
+		{
+			name:     "DATEがない場合は日付形式のBasenameから日時を推測",
+			pattern:  "{{.Year}}/{{.Month}}/{{.Day}}/{{.Hour}}{{.Minute}}{{.Second}}.md",
+			entry:    entry.Entry{Title: "Basename Only", Basename: "2022/12/31/235959"},
+			expected: "2022/12/31/235959.md",
+		},
+		{
+			name:     "危険な文字はディレクトリの区切りにならない",
+			pattern:  "{{.Title}}.md",
+			entry:    entry.Entry{Title: "a/b\\c"},
+			expected: "a_b_c.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseFilenameTemplate(tt.pattern)
+			if err != nil {
+				t.Fatalf("ParseFilenameTemplate failed: %v", err)
+			}
+			result, err := tmpl.Execute(tt.entry, 7)
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Execute() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilenameTemplateErrors(t *testing.T) {
+	for _, pattern := range []string{"{{.Title", "{{.Unknown}}.md"} {
+		if _, err := ParseFilenameTemplate(pattern); err == nil {
+			t.Errorf("Expected parse error for %q", pattern)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+	}{
+		{name: "空のファイル名", pattern: "{{.Basename}}"},
+		{name: "ディレクトリのみ", pattern: "{{.Year}}/"},
+		{name: "絶対パス", pattern: "/{{.Slug}}.md"},
+		{name: "出力ディレクトリの外", pattern: "../{{.Slug}}.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseFilenameTemplate(tt.pattern)
+			if err != nil {
+				t.Fatalf("ParseFilenameTemplate failed: %v", err)
+			}
+			if result, err := tmpl.Execute(entry.Entry{Title: "Test"}, 1); err == nil {
+				t.Errorf("Expected error, got %q", result)
+			}
+		})
+	}
+}
+
+func TestMTFilename(t *testing.T) {
+	tests := map[string]string{
+		"2023-01-15_Test.md": "2023-01-15_Test.txt",
+		"README.md_memo.md":  "README.md_memo.txt",
+		"2023/01/123456.md":  "2023/01/123456.txt",
+		"no-extension":       "no-extension.txt",
+	}
+	for input, expected := range tests {
+		if result := MTFilename(input); result != expected {
+			t.Errorf("MTFilename(%q) = %q, want %q", input, result, expected)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package generator
+
+import (
+	"cmp"
+	"io"
+	"slices"
+	"strings"
+
+	"mttohmd/entry"
+)
+
+// EntrySeparator MT形式のエクスポートでエントリー同士を区切る行
+const EntrySeparator = "--------"
+
+// sectionTerminator ヘッダーと複数行セクションの終わりを表す行
+const sectionTerminator = "-----"
+
+// Writer 複数のエントリーを1つのMT形式のエクスポートとして書き出す
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter io.Writerに書き出すWriterを作成
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write エントリーを書き出し、続けてエントリー区切りを出力
+func (w *Writer) Write(e entry.Entry) error {
+	_, err := io.WriteString(w.w, GenerateMTContent(e)+EntrySeparator+"\n")
+	return err
+}
+
+// GenerateExport エントリー一覧をMT形式のエクスポート全体として出力
+func GenerateExport(entries []entry.Entry) string {
+	var sb strings.Builder
+	w := NewWriter(&sb)
+	for _, e := range entries {
+		w.Write(e)
+	}
+	return sb.String()
+}
+
+// GenerateMTContent エントリーをMovableType形式のまま出力
+// ヘッダー、BODY、EXTENDED BODY、EXCERPT、KEYWORDS、COMMENT、PINGの順に、
+// 値のあるものだけを出力する（BODYは空でも必ず出力）。
+// 既知のヘッダーはheaderFieldsの順に並べ、Extraは解析時の位置に戻すため、
+// 既知のヘッダーがこの順に並んだ入力（はてなブログのエクスポートなど）は解析→生成でバイト単位まで元に戻る
+func GenerateMTContent(e entry.Entry) string {
+	var mt strings.Builder
+
+	// MovableType形式のヘッダー
+	for _, f := range headerFields(e) {
+		mt.WriteString(f.Key)
+		mt.WriteString(": ")
+		mt.WriteString(f.Value)
+		mt.WriteString("\n")
+	}
+	mt.WriteString(sectionTerminator + "\n")
+
+	mt.WriteString("BODY:\n")
+	writeBlock(&mt, e.Body)
+
+	// 追記・概要・キーワードは値がある場合のみ出力
+	writeSection(&mt, "EXTENDED BODY", e.ExtendedBody)
+	writeSection(&mt, "EXCERPT", e.Excerpt)
+	writeSection(&mt, "KEYWORDS", e.Keywords)
+
+	for _, c := range e.Comments {
+		writeComment(&mt, c)
+	}
+
+	for _, p := range e.Pings {
+		writePing(&mt, p)
+	}
+
+	return mt.String()
+}
+
+// headerFields ヘッダー行を出力する順に返す（TITLE以外の空の値は省略）
+// 既知のキーはAUTHOR、TITLE、BASENAME、STATUS、ALLOW COMMENTS、ALLOW PINGS、CONVERT BREAKS、
+// DATE、PRIMARY CATEGORY、CATEGORY、IMAGEの順に並べ、ExtraはPositionの行に差し込む。
+// 位置の記録がないExtraはCONVERT BREAKSの後に出現順のまま出力する
+func headerFields(e entry.Entry) []entry.Field {
+	var known []entry.Field
+	add := func(key, value string) {
+		if value != "" {
+			known = append(known, entry.Field{Key: key, Value: value})
+		}
+	}
+
+	add("AUTHOR", e.Author)
+	known = append(known, entry.Field{Key: "TITLE", Value: e.Title})
+	add("BASENAME", e.Basename)
+	add("STATUS", string(e.Status))
+	add("ALLOW COMMENTS", e.AllowComments.String())
+	add("ALLOW PINGS", e.AllowPings.String())
+	add("CONVERT BREAKS", string(e.ConvertBreaks))
+
+	var positioned []entry.Field
+	for _, f := range e.Extra {
+		if f.Position > 0 {
+			positioned = append(positioned, f)
+		} else {
+			known = append(known, f)
+		}
+	}
+
+	// 元の表記があればそのまま、なければ解析済みの日時から出力
+	if e.Date != "" {
+		add("DATE", e.Date)
+	} else if !e.DateTime.IsZero() {
+		add("DATE", entry.FormatDate(e.DateTime))
+	}
+
+	// 主カテゴリーが明示されている場合はPRIMARY CATEGORYを先に出力
+	for _, c := range e.Categories {
+		if c.Primary {
+			known = append(known, entry.Field{Key: "PRIMARY CATEGORY", Value: c.Name})
+		}
+	}
+	for _, c := range e.Categories {
+		known = append(known, entry.Field{Key: "CATEGORY", Value: c.Name})
+	}
+	add("IMAGE", e.ImageURL)
+
+	// 位置の記録があるExtraを、その行に来るまで既知のヘッダーを出力してから差し込む
+	slices.SortStableFunc(positioned, func(a, b entry.Field) int {
+		return cmp.Compare(a.Position, b.Position)
+	})
+	fields := make([]entry.Field, 0, len(known)+len(positioned))
+	for len(known) > 0 || len(positioned) > 0 {
+		if len(positioned) > 0 && (len(known) == 0 || positioned[0].Position <= len(fields)+1) {
+			fields = append(fields, positioned[0])
+			positioned = positioned[1:]
+		} else {
+			fields = append(fields, known[0])
+			known = known[1:]
+		}
+	}
+	return fields
+}
+
+// writeSection 複数行セクションを出力（空の場合は何もしない）
+func writeSection(mt *strings.Builder, name, value string) {
+	if value == "" {
+		return
+	}
+	mt.WriteString(name)
+	mt.WriteString(":\n")
+	writeBlock(mt, value)
+}
+
+// writeComment COMMENTセクションを出力（空のメタデータは省略）
+func writeComment(mt *strings.Builder, c entry.Comment) {
+	mt.WriteString("COMMENT:\n")
+	writeField(mt, "AUTHOR", c.Author)
+	writeField(mt, "EMAIL", c.Email)
+	writeField(mt, "IP", c.IP)
+	writeField(mt, "URL", c.URL)
+	writeField(mt, "DATE", c.Date)
+	writeBlock(mt, c.Body)
+}
+
+// writePing PINGセクションを出力（空のメタデータは省略）
+func writePing(mt *strings.Builder, p entry.Ping) {
+	mt.WriteString("PING:\n")
+	writeField(mt, "TITLE", p.Title)
+	writeField(mt, "URL", p.URL)
+	writeField(mt, "IP", p.IP)
+	writeField(mt, "BLOG NAME", p.BlogName)
+	writeField(mt, "DATE", p.Date)
+	writeBlock(mt, p.Excerpt)
+}
+
+// writeBlock 複数行の値とセクションの終わりを出力（空の値は行を出力しない）
+func writeBlock(mt *strings.Builder, value string) {
+	if value != "" {
+		mt.WriteString(value)
+		mt.WriteString("\n")
+	}
+	mt.WriteString(sectionTerminator + "\n")
+}
+
+// writeField メタデータ行を出力（空の場合は何もしない）
+func writeField(mt *strings.Builder, key, value string) {
+	if value == "" {
+		return
+	}
+	mt.WriteString(key)
+	mt.WriteString(": ")
+	mt.WriteString(value)
+	mt.WriteString("\n")
+}
@@ -0,0 +1,328 @@
+package generator
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"mttohmd/entry"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	for _, title := range []string{"Entry 1", "Entry 2"} {
+		if err := w.Write(entry.Entry{Title: title, Body: "本文"}); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	expected := "TITLE: Entry 1\n-----\nBODY:\n本文\n-----\n--------\n" +
+		"TITLE: Entry 2\n-----\nBODY:\n本文\n-----\n--------\n"
+	if buf.String() != expected {
+		t.Errorf("Writer output = %q, want %q", buf.String(), expected)
+	}
+
+	if result := GenerateExport([]entry.Entry{{Title: "Entry 1", Body: "本文"}, {Title: "Entry 2", Body: "本文"}}); result != expected {
+		t.Errorf("GenerateExport() = %q, want %q", result, expected)
+	}
+}
+
+func TestGenerateMTContentEmptySections(t *testing.T) {
+	// 空の本文やコメント本文では空行を出力しない
+	result := GenerateMTContent(entry.Entry{
+		Title:    "Empty",
+		Comments: []entry.Comment{{Author: "commenter"}},
+	})
+
+	expected := "TITLE: Empty\n-----\nBODY:\n-----\nCOMMENT:\nAUTHOR: commenter\n-----\n"
+	if result != expected {
+		t.Errorf("GenerateMTContent() = %q, want %q", result, expected)
+	}
+}
+
+// knownHeaderKeys パーサーが解釈するヘッダーのキー（Extraには使えない）
+var knownHeaderKeys = map[string]bool{
+	"AUTHOR": true, "TITLE": true, "BASENAME": true, "STATUS": true, "DATE": true,
+	"ALLOW COMMENTS": true, "ALLOW PINGS": true, "CONVERT BREAKS": true,
+	"PRIMARY CATEGORY": true, "CATEGORY": true, "IMAGE": true,
+}
+
+// sectionNames 複数行セクションの名前
+var sectionNames = map[string]bool{
+	"BODY": true, "EXTENDED BODY": true, "EXCERPT": true, "KEYWORDS": true, "COMMENT": true, "PING": true,
+}
+
+// representable MT形式で曖昧さなく表現できるエントリーかどうか
+// （改行を含むヘッダー値や、構造と区別できない区切り線を含む本文などは除く）
+func representable(e entry.Entry) bool {
+	// 文字コードの自動判別でUTF-8と判定されるよう不正なバイト列は除く
+	if e.Title == "" || !utf8.ValidString(GenerateMTContent(e)) {
+		return false
+	}
+
+	singleLines := []string{e.Author, e.Title, e.Basename, string(e.Status), string(e.ConvertBreaks), e.Date, e.ImageURL}
+	for _, c := range e.Categories {
+		singleLines = append(singleLines, c.Name)
+	}
+	for _, f := range e.Extra {
+		if !isExtraKey(f.Key) {
+			return false
+		}
+		singleLines = append(singleLines, f.Value)
+	}
+	for _, c := range e.Comments {
+		singleLines = append(singleLines, c.Author, c.Email, c.IP, c.URL, c.Date)
+		if !representableBlock(c.Body) || startsWithMeta(c.Body, "AUTHOR", "EMAIL", "IP", "URL", "DATE") {
+			return false
+		}
+	}
+	for _, p := range e.Pings {
+		singleLines = append(singleLines, p.Title, p.URL, p.IP, p.BlogName, p.Date)
+		if !representableBlock(p.Excerpt) || startsWithMeta(p.Excerpt, "TITLE", "URL", "IP", "BLOG NAME", "DATE") {
+			return false
+		}
+	}
+	for _, s := range singleLines {
+		if strings.ContainsAny(s, "\r\n") {
+			return false
+		}
+	}
+
+	for _, block := range []string{e.Body, e.ExtendedBody, e.Excerpt, e.Keywords} {
+		if !representableBlock(block) {
+			return false
+		}
+	}
+
+	// カテゴリー名は重複せず、主カテゴリーは1つまで
+	names := map[string]bool{}
+	primaries := 0
+	for _, c := range e.Categories {
+		if names[c.Name] {
+			return false
+		}
+		names[c.Name] = true
+		if c.Primary {
+			primaries++
+		}
+	}
+	if primaries > 1 {
+		return false
+	}
+
+	// DATEは空か、DateTimeと一致する表記
+	if e.Date == "" {
+		return e.DateTime.IsZero()
+	}
+	t, err := entry.ParseDate(e.Date, nil)
+	return err == nil && reflect.DeepEqual(t, e.DateTime)
+}
+
+// representableBlock 複数行の値の中の区切り線が構造と誤認されないかどうか
+func representableBlock(value string) bool {
+	if strings.Contains(value, "\r") {
+		return false
+	}
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		if line != "-----" && line != "--------" {
+			continue
+		}
+		next, ok := nextNonBlank(lines[i+1:])
+		if !ok {
+			continue
+		}
+		if line == "-----" {
+			name, isSection := strings.CutSuffix(next, ":")
+			if (isSection && sectionNames[name]) || next == "--------" {
+				return false
+			}
+		} else if isHeaderLike(next) {
+			return false
+		}
+	}
+	return true
+}
+
+// nextNonBlank 最初の空行でない行を返す
+func nextNonBlank(lines []string) (string, bool) {
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return line, true
+		}
+	}
+	return "", false
+}
+
+// startsWithMeta 1行目がメタデータ行と誤認されるかどうか
+func startsWithMeta(value string, keys ...string) bool {
+	key, _, ok := strings.Cut(value, ":")
+	if !ok || strings.Contains(key, "\n") {
+		return false
+	}
+	for _, k := range keys {
+		if key == k {
+			return true
+		}
+	}
+	return false
+}
+
+// isHeaderLike 「KEY: 値」形式の行かどうか
+func isHeaderLike(line string) bool {
+	key, _, ok := strings.Cut(line, ":")
+	return ok && key != "" && isExtraKeyChars(key)
+}
+
+// isExtraKey Extraのキーとして往復できるかどうか
+func isExtraKey(key string) bool {
+	return key != "" && isExtraKeyChars(key) && !knownHeaderKeys[key] && !sectionNames[key]
+}
+
+func isExtraKeyChars(key string) bool {
+	for _, r := range key {
+		if !('A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == ' ' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// assertRoundTrip 生成したMT形式を解析すると元のエントリーに戻ることを確認
+func assertRoundTrip(t *testing.T, entries []entry.Entry) {
+	t.Helper()
+	export := GenerateExport(entries)
+	parsed, err := entry.ParseReader(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("ParseReader failed: %v", err)
+	}
+	// 解析結果からの再生成も同じ出力になる（Extraの位置も保たれる）
+	if again := GenerateExport(parsed); again != export {
+		t.Fatalf("Regenerated export differs:\ngot:\n%s\nwant:\n%s", again, export)
+	}
+
+	// 生成元のExtraには位置の記録がないため、解析時に記録された位置を除いて比較する
+	for i := range parsed {
+		for j := range parsed[i].Extra {
+			parsed[i].Extra[j].Position = 0
+		}
+	}
+	if !reflect.DeepEqual(parsed, entries) {
+		t.Fatalf("Round trip mismatch:\nexport:\n%s\ngot:  %#v\nwant: %#v", export, parsed, entries)
+	}
+}
+
+// fragments ランダムなエントリーの生成に使う文字列（区切り線やヘッダーに似た行を含む）
+var fragments = []string{
+	"", " ", "本文", "日本語のテキスト", "a: b", "TITLE: 偽のタイトル", "AUTHOR: someone",
+	"URL: https://example.com/", "-----", "--------", "BODY:", "EXCERPT:", "COMMENT:",
+	"<p>段落</p>", "  字下げ", "\t", "x-y_z", "末尾に空白 ", "# 見出し",
+}
+
+func randomText(r *rand.Rand, maxLines int) string {
+	n := r.IntN(maxLines + 1)
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fragments[r.IntN(len(fragments))]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func randomLine(r *rand.Rand) string {
+	return fragments[r.IntN(len(fragments))]
+}
+
+func randomEntry(r *rand.Rand) entry.Entry {
+	e := entry.Entry{
+		Title:         "タイトル" + randomLine(r),
+		Author:        randomLine(r),
+		Basename:      randomLine(r),
+		Status:        []entry.Status{"", entry.StatusPublish, entry.StatusDraft, "Unknown"}[r.IntN(4)],
+		AllowComments: entry.Flag(r.IntN(3)),
+		AllowPings:    entry.Flag(r.IntN(3)),
+		ConvertBreaks: []entry.ConvertBreaks{"", entry.ConvertBreaksNone, entry.ConvertBreaksMarkdown, "custom"}[r.IntN(4)],
+		Body:          randomText(r, 6),
+		ExtendedBody:  randomText(r, 3),
+		Excerpt:       randomText(r, 2),
+		Keywords:      randomText(r, 1),
+		ImageURL:      randomLine(r),
+	}
+
+	if r.IntN(2) == 0 {
+		e.Date = []string{"01/15/2023 03:04:05 PM", "12/31/2022 23:59:59", "1/2/2006 3:04:05 AM"}[r.IntN(3)]
+		e.DateTime, _ = entry.ParseDate(e.Date, nil)
+	}
+	for i := range r.IntN(4) {
+		e.Categories = append(e.Categories, entry.Category{Name: randomLine(r) + string(rune('A'+i)), Primary: i == 1})
+	}
+	for range r.IntN(3) {
+		e.Extra = append(e.Extra, entry.Field{Key: []string{"NO ENTRY", "TAGS", "X-CUSTOM"}[r.IntN(3)], Value: randomLine(r)})
+	}
+	for range r.IntN(3) {
+		e.Comments = append(e.Comments, entry.Comment{Author: randomLine(r), URL: randomLine(r), Date: randomLine(r), Body: randomText(r, 3)})
+	}
+	for range r.IntN(2) {
+		e.Pings = append(e.Pings, entry.Ping{Title: randomLine(r), URL: randomLine(r), BlogName: randomLine(r), Excerpt: randomText(r, 2)})
+	}
+	return e
+}
+
+func TestGenerateMTContentRoundTripProperty(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+
+	checked := 0
+	for range 2000 {
+		var entries []entry.Entry
+		for range 1 + r.IntN(3) {
+			if e := randomEntry(r); representable(e) {
+				entries = append(entries, e)
+			}
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		assertRoundTrip(t, entries)
+		checked++
+	}
+
+	if checked < 100 {
+		t.Errorf("Too few representable samples: %d", checked)
+	}
+}
+
+func FuzzGenerateMTContentRoundTrip(f *testing.F) {
+	f.Add("タイトル", "author", "本文\n-----\n続き", "追記", "概要", "カテゴリー", "X-CUSTOM", "値", "コメント", uint8(0))
+	f.Add("Title", "", "", "", "", "", "TAGS", "", "", uint8(255))
+	f.Add("区切り", "a", "--------\n本文中の区切り", "-----", "", "Go", "NO ENTRY", "1", "-----\n--------", uint8(7))
+
+	f.Fuzz(func(t *testing.T, title, author, body, extended, excerpt, category, extraKey, extraValue, commentBody string, flags uint8) {
+		e := entry.Entry{
+			Title:         title,
+			Author:        author,
+			AllowComments: entry.Flag(flags % 3),
+			AllowPings:    entry.Flag(flags / 3 % 3),
+			Body:          body,
+			ExtendedBody:  extended,
+			Excerpt:       excerpt,
+		}
+		if flags&0x80 != 0 {
+			e.Categories = []entry.Category{{Name: category, Primary: flags&0x40 != 0}}
+		}
+		if extraKey != "" {
+			e.Extra = []entry.Field{{Key: extraKey, Value: extraValue}}
+		}
+		if commentBody != "" {
+			e.Comments = []entry.Comment{{Author: author, Body: commentBody}}
+		}
+		if !representable(e) {
+			t.Skip()
+		}
+
+		assertRoundTrip(t, []entry.Entry{e, {Title: "次のエントリー", Body: body}})
+	})
+}
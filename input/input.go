@@ -0,0 +1,124 @@
+package input
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// Stdin 標準入力を表すパス
+const Stdin = "-"
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zipMagic  = []byte("PK\x03\x04")
+)
+
+// WalkFunc Walkが展開したエクスポートファイルごとに呼ぶ関数
+// onlyはそのファイルがパスに含まれる唯一のエクスポートかどうか（複数のファイルを含むzipの中ではfalse）で、
+// 読み始める前に他のエクスポートとのマージが必要かどうかを判断できる
+type WalkFunc func(name string, r io.Reader, only bool) error
+
+// Walk パスの内容を展開しながらエクスポートファイルを1つずつfnに渡す
+// "-" は標準入力、gzipは展開して、zipは含まれる全てのファイルを順に渡す。
+// 形式は拡張子（.gz / .zip）か先頭のマジックバイトで判定する
+func Walk(name string, fn WalkFunc) error {
+	if name == Stdin {
+		return walkReader("stdin", os.Stdin, true, fn)
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return walkReader(name, file, true, fn)
+}
+
+// walkReader 形式を判定して展開しながらfnに渡す
+func walkReader(name string, r io.Reader, only bool, fn WalkFunc) error {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(len(zipMagic))
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	ext := strings.ToLower(path.Ext(name))
+	switch {
+	case ext == ".gz" || bytes.HasPrefix(head, gzipMagic):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return fmt.Errorf("%s: gzipの展開に失敗しました: %w", name, err)
+		}
+		defer gz.Close()
+		return walkReader(strings.TrimSuffix(name, path.Ext(name)), gz, only, fn)
+	case ext == ".zip" || bytes.HasPrefix(head, zipMagic):
+		return walkZip(name, r, br, only, fn)
+	}
+	return fn(name, br, only)
+}
+
+// walkZip zipに含まれる全てのファイルを順にfnに渡す（ディレクトリや隠しファイルは除く）
+// 元の入力がファイルであればそのまま開き、標準入力などはメモリに読み込んでから開く
+func walkZip(name string, orig io.Reader, br *bufio.Reader, only bool, fn WalkFunc) error {
+	var ra io.ReaderAt
+	var size int64
+	if f, ok := orig.(*os.File); ok && f != os.Stdin {
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		ra, size = f, info.Size()
+	} else {
+		data, err := io.ReadAll(br)
+		if err != nil {
+			return err
+		}
+		ra, size = bytes.NewReader(data), int64(len(data))
+	}
+
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return fmt.Errorf("%s: zipの展開に失敗しました: %w", name, err)
+	}
+
+	var files []*zip.File
+	for _, f := range zr.File {
+		if !f.FileInfo().IsDir() && !isHiddenPath(f.Name) {
+			files = append(files, f)
+		}
+	}
+	for _, f := range files {
+		if err := walkZipFile(name+":"+f.Name, f, only && len(files) == 1, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkZipFile zip内の1ファイルを開いてfnに渡す
+func walkZipFile(name string, f *zip.File, only bool, fn WalkFunc) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	defer rc.Close()
+	return walkReader(name, rc, only, fn)
+}
+
+// isHiddenPath macOSのリソースフォーク（__MACOSX）やドットファイルかどうか
+func isHiddenPath(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return true
+		}
+	}
+	return false
+}
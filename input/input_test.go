@@ -0,0 +1,208 @@
+package input
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// walked Walkで渡されたファイル名と内容、唯一のエクスポートかどうか
+type walked struct {
+	Name    string
+	Content string
+	Only    bool
+}
+
+func walkAll(t *testing.T, name string) []walked {
+	t.Helper()
+	var result []walked
+	err := Walk(name, func(name string, r io.Reader, only bool) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		result = append(result, walked{Name: name, Content: string(data), Only: only})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk(%q) failed: %v", name, err)
+	}
+	return result
+}
+
+func gzipBytes(t *testing.T, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zipBytes(t *testing.T, files map[string][]byte, order []string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range order {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(files[name]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestWalk(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	zipped := zipBytes(t, map[string][]byte{
+		"export1.txt":          []byte("TITLE: 1\n"),
+		"dir/":                 nil,
+		"dir/export2.txt.gz":   gzipBytes(t, "TITLE: 2\n"),
+		"__MACOSX/._export1":   []byte("resource fork"),
+		".DS_Store":            []byte("ds store"),
+		"dir/.hidden/skip.txt": []byte("hidden"),
+	}, []string{"export1.txt", "dir/", "dir/export2.txt.gz", "__MACOSX/._export1", ".DS_Store", "dir/.hidden/skip.txt"})
+
+	files := map[string][]byte{
+		"plain.txt":     []byte("TITLE: plain\n"),
+		"export.txt.gz": gzipBytes(t, "TITLE: gzip\n"),
+		"noext":         gzipBytes(t, "TITLE: magic\n"),
+		"export.zip":    zipped,
+		"zip.bin":       zipped,
+		"single.zip": zipBytes(t, map[string][]byte{
+			"export.txt": []byte("TITLE: single\n"),
+			".DS_Store":  []byte("ds store"),
+		}, []string{"export.txt", ".DS_Store"}),
+		"nested.zip": zipBytes(t, map[string][]byte{"inner.zip": zipped}, []string{"inner.zip"}),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		expected []walked
+	}{
+		{
+			name:     "非圧縮",
+			file:     "plain.txt",
+			expected: []walked{{Name: "plain.txt", Content: "TITLE: plain\n", Only: true}},
+		},
+		{
+			name:     "gzip（拡張子で判定）",
+			file:     "export.txt.gz",
+			expected: []walked{{Name: "export.txt", Content: "TITLE: gzip\n", Only: true}},
+		},
+		{
+			name:     "gzip（マジックバイトで判定）",
+			file:     "noext",
+			expected: []walked{{Name: "noext", Content: "TITLE: magic\n", Only: true}},
+		},
+		{
+			name: "zip（拡張子で判定）",
+			file: "export.zip",
+			expected: []walked{
+				{Name: "export.zip:export1.txt", Content: "TITLE: 1\n"},
+				{Name: "export.zip:dir/export2.txt", Content: "TITLE: 2\n"},
+			},
+		},
+		{
+			name: "zip（マジックバイトで判定）",
+			file: "zip.bin",
+			expected: []walked{
+				{Name: "zip.bin:export1.txt", Content: "TITLE: 1\n"},
+				{Name: "zip.bin:dir/export2.txt", Content: "TITLE: 2\n"},
+			},
+		},
+		{
+			name:     "ファイルが1つだけのzip",
+			file:     "single.zip",
+			expected: []walked{{Name: "single.zip:export.txt", Content: "TITLE: single\n", Only: true}},
+		},
+		{
+			name: "zipの中の複数のファイルを含むzip",
+			file: "nested.zip",
+			expected: []walked{
+				{Name: "nested.zip:inner.zip:export1.txt", Content: "TITLE: 1\n"},
+				{Name: "nested.zip:inner.zip:dir/export2.txt", Content: "TITLE: 2\n"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := walkAll(t, filepath.Join(tmpDir, tt.file))
+			for i := range tt.expected {
+				tt.expected[i].Name = filepath.Join(tmpDir, tt.expected[i].Name)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Walk() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWalkStdin(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		w.Write(gzipBytes(t, "TITLE: stdin\n"))
+		w.Close()
+	}()
+
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = stdin }()
+
+	result := walkAll(t, Stdin)
+	expected := []walked{{Name: "stdin", Content: "TITLE: stdin\n", Only: true}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Walk(-) = %+v, want %+v", result, expected)
+	}
+}
+
+func TestWalkErrors(t *testing.T) {
+	if err := Walk("non_existent_file.txt", func(string, io.Reader, bool) error { return nil }); err == nil {
+		t.Error("Expected error for non-existent file, got nil")
+	}
+
+	tmpDir := t.TempDir()
+	broken := filepath.Join(tmpDir, "broken.zip")
+	if err := os.WriteFile(broken, []byte("not a zip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Walk(broken, func(string, io.Reader, bool) error { return nil }); err == nil {
+		t.Error("Expected error for broken zip, got nil")
+	}
+
+	// fnのエラーはそのまま返される
+	plain := filepath.Join(tmpDir, "plain.txt")
+	if err := os.WriteFile(plain, []byte("TITLE: x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stop := io.ErrUnexpectedEOF
+	if err := Walk(plain, func(string, io.Reader, bool) error { return stop }); err != stop {
+		t.Errorf("Expected fn error, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"mttohmd/entry"
+	"mttohmd/generator"
+)
+
+// defaultJoinOutput joinの既定の出力ファイル
+const defaultJoinOutput = "mts.export.txt"
+
+// parseOptions MT形式のファイルを解析するときのオプション
+type parseOptions struct {
+	strict   bool
+	location *time.Location
+	encoding entry.Encoding
+}
+
+// newParser オプションを設定したパーサーを作成
+func (o parseOptions) newParser(name string, r io.Reader) *entry.Parser {
+	parser := entry.NewParser(r)
+	parser.Filename = name
+	parser.Strict = o.strict
+	parser.Location = o.location
+	parser.Encoding = o.encoding
+	return parser
+}
+
+// runJoin エントリーごとのMT形式のファイル（ディレクトリ指定時は配下の*.txt）を読み込み、
+// 日付順に並べて1つのMT形式のエクスポートとして出力
+func runJoin(paths []string, output string, opts parseOptions) error {
+	if output == "" {
+		output = defaultJoinOutput
+	}
+
+	files, err := listFiles(paths, ".txt")
+	if err != nil {
+		return err
+	}
+
+	var entries []entry.Entry
+	var diagnostics []entry.Diagnostic
+	for _, filename := range files {
+		parsed, diags, err := parseFile(filename, opts)
+		diagnostics = append(diagnostics, diags...)
+		if err != nil {
+			return fmt.Errorf("ファイル解析エラー: %w", err)
+		}
+		if len(parsed) == 0 {
+			fmt.Printf("警告: %s にエントリーがありません\n", filename)
+		}
+		entries = append(entries, parsed...)
+	}
+
+	sortByDate(entries)
+
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := generator.NewWriter(file)
+	for _, e := range entries {
+		if err := w.Write(e); err != nil {
+			return err
+		}
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	printDiagnostics(diagnostics)
+	fmt.Printf("変換完了！ %d個のファイルから%d個のエントリーを %s に出力しました\n", len(files), len(entries), output)
+	return nil
+}
+
+// parseFile MT形式のファイルを解析し、エントリーと診断を返す
+func parseFile(filename string, opts parseOptions) ([]entry.Entry, []entry.Diagnostic, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	parser := opts.newParser(filename, file)
+	var entries []entry.Entry
+	for e, err := range parser.All() {
+		if err != nil {
+			return entries, parser.Diagnostics(), err
+		}
+		entries = append(entries, e)
+	}
+	return entries, parser.Diagnostics(), nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"mttohmd/entry"
+	"mttohmd/generator"
+)
+
+func TestRunJoin(t *testing.T) {
+	// convertで出力したエントリーごとのmts/を1つのエクスポートに戻す
+	jst := time.FixedZone("JST", 9*60*60)
+	dated := func(title, date string) entry.Entry {
+		dt, err := entry.ParseDate(date, jst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return entry.Entry{Title: title, Date: date, DateTime: dt, Status: entry.StatusPublish, Body: "<p>" + title + "</p>"}
+	}
+
+	first := dated("1月1日", "01/01/2023 10:00:00")
+	first.Categories = []entry.Category{{Name: "Go"}}
+	first.Extra = []entry.Field{{Key: "X-CUSTOM", Value: "値"}}
+	first.Comments = []entry.Comment{{Author: "commenter", Date: "01/01/2023 12:00:00", Body: "コメント"}}
+	second := dated("1月2日", "01/02/2023 10:00:00")
+	third := dated("1月3日", "01/03/2023 10:00:00")
+	undated1 := entry.Entry{Title: "日付なし1", Status: entry.StatusDraft, Body: "下書き"}
+	undated2 := entry.Entry{Title: "日付なし2", Body: "下書き"}
+
+	dir := t.TempDir()
+	mts := filepath.Join(dir, "mts")
+	files := map[string]string{
+		// ファイル名の順ではなく日付順に並び、日付のないエントリーは名前順のまま最後に来る
+		"0_undated1.txt":         generator.GenerateMTContent(undated1),
+		"1_undated2.txt":         generator.GenerateMTContent(undated2),
+		"2023/01/02/second.txt":  generator.GenerateMTContent(second),
+		"2023/01/01/first.txt":   generator.GenerateMTContent(first),
+		"third.TXT":              generator.GenerateMTContent(third),
+		"notes.md":               "TITLE: 拡張子が違う\n-----\nBODY:\nx\n-----\n",
+		".hidden.txt":            "TITLE: ドットファイル\n-----\nBODY:\nx\n-----\n",
+		".git/objects/entry.txt": "TITLE: ドットディレクトリ\n-----\nBODY:\nx\n-----\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(mts, filepath.FromSlash(name))
+		if err := writeFile(path, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output := filepath.Join(dir, "mts.export.txt")
+	if err := runJoin([]string{mts}, output, parseOptions{location: jst}); err != nil {
+		t.Fatalf("runJoin failed: %v", err)
+	}
+
+	entries, err := entry.ParseEntries(output)
+	if err != nil {
+		t.Fatalf("ParseEntries failed: %v", err)
+	}
+	var titles []string
+	for _, e := range entries {
+		titles = append(titles, e.Title)
+	}
+	expectedTitles := []string{"1月1日", "1月2日", "1月3日", "日付なし1", "日付なし2"}
+	if !reflect.DeepEqual(titles, expectedTitles) {
+		t.Fatalf("Titles = %v, want %v", titles, expectedTitles)
+	}
+
+	// 各エントリーの内容はconvertが出力したファイルのまま
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := generator.GenerateExport([]entry.Entry{first, second, third, undated1, undated2})
+	if string(data) != expected {
+		t.Errorf("Export mismatch:\ngot:\n%s\nwant:\n%s", data, expected)
+	}
+	if entries[0].Comments[0].Body != "コメント" || entries[3].Status != entry.StatusDraft {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+}
+
+func TestRunJoinFiles(t *testing.T) {
+	// ファイルを直接指定した場合は拡張子によらず読み込み、存在しないパスはエラー
+	dir := t.TempDir()
+	path := filepath.Join(dir, "entry.mt")
+	if err := os.WriteFile(path, []byte("TITLE: 直接指定\n-----\nBODY:\n本文\n-----\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(dir, "out.txt")
+	if err := runJoin([]string{path}, output, parseOptions{}); err != nil {
+		t.Fatalf("runJoin failed: %v", err)
+	}
+	entries, err := entry.ParseEntries(output)
+	if err != nil {
+		t.Fatalf("ParseEntries failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Title != "直接指定" {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+
+	if err := runJoin([]string{filepath.Join(dir, "missing")}, output, parseOptions{}); err == nil {
+		t.Error("Expected error for missing path, got nil")
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// runList エントリーの一覧を番号・日付・状態・カテゴリー・タイトルの表として表示
+// 番号はshowの -index で指定する番号と同じ（-offset で読み飛ばしても変わらない）
+func runList(cfg config) error {
+	result, err := loadEntries(cfg)
+	if err != nil {
+		return err
+	}
+
+	entries := result.entries
+	start, end := cfg.entryRange(len(entries))
+
+	rows := [][]string{{"#", "日付", "状態", "カテゴリー", "タイトル"}}
+	for i := start; i < end; i++ {
+		e := entries[i]
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			formatEntryDate(e),
+			string(e.Status),
+			strings.Join(e.CategoryNames(), ", "),
+			e.Title,
+		})
+	}
+	writeTable(os.Stdout, rows)
+
+	printLoadWarnings(result)
+	return nil
+}
+
+// writeTable 列の表示幅を揃えて表を出力（全角文字は2桁として数える）
+func writeTable(w io.Writer, rows [][]string) {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], displayWidth(cell))
+		}
+	}
+
+	for _, row := range rows {
+		var b strings.Builder
+		for i, cell := range row {
+			b.WriteString(cell)
+			// 最後の列は右側を空白で埋めない
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)+2))
+			}
+		}
+		fmt.Fprintln(w, b.String())
+	}
+}
+
+// displayWidth 端末での表示幅（東アジアの全角文字は2、それ以外は1）
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if isWide(r) {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+// isWide 全角で表示される文字かどうか（ハングル・CJK・全角形など主な範囲のみ）
+func isWide(r rune) bool {
+	switch {
+	case r >= 0x1100 && r <= 0x115F,
+		r >= 0x2E80 && r <= 0x303E,
+		r >= 0x3041 && r <= 0x33FF,
+		r >= 0x3400 && r <= 0x4DBF,
+		r >= 0x4E00 && r <= 0x9FFF,
+		r >= 0xA000 && r <= 0xA4CF,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1F64F,
+		r >= 0x1F900 && r <= 0x1F9FF,
+		r >= 0x20000 && r <= 0x3FFFD:
+		return true
+	}
+	return false
+}
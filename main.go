@@ -1,80 +1,193 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"strings"
 
-	"mttohmd/converter"
 	"mttohmd/entry"
 	"mttohmd/generator"
+	"mttohmd/input"
+	"mttohmd/merger"
 )
 
 func main() {
-	filename := "blog.basyura.org.export.txt"
-
-	// ファイルの存在確認
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		fmt.Printf("エラー: %s が見つかりません\n", filename)
-		os.Exit(1)
+	cmd, cfg, err := parseArgs(os.Args[1:], os.Stderr)
+	if err == flag.ErrHelp {
+		return
 	}
-
-	// エントリーの解析
-	entries, err := entry.ParseEntries(filename)
 	if err != nil {
-		fmt.Printf("ファイル解析エラー: %v\n", err)
+		fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+		if cmd.name != "" {
+			fmt.Fprintf(os.Stderr, "使い方は mttohmd %s -h で確認できます\n", cmd.name)
+		} else {
+			fmt.Fprintln(os.Stderr, "使い方は mttohmd -h で確認できます")
+		}
+		os.Exit(2)
+	}
+
+	if err := cmd.run(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
 		os.Exit(1)
 	}
+}
 
-	fmt.Printf("解析完了: %d個のエントリーが見つかりました\n", len(entries))
+// loadResult loadEntriesで読み込んだエントリーと解析結果
+type loadResult struct {
+	entries     []entry.Entry
+	diagnostics []entry.Diagnostic
+	encodings   []sourceEncoding // 読み込んだエクスポートごとの文字コード
+	merge       *merger.Report   // 複数のエクスポートをマージした場合のみ
+	drafts      int              // -skip-drafts で除外した下書きの件数
+}
 
-	// 出力ディレクトリの作成
-	mtsDir := "mts"
-	mdsDir := "mds"
+// sourceEncoding エクスポートの名前と判定した文字コード
+type sourceEncoding struct {
+	name     string
+	encoding entry.Encoding
+}
 
-	if err := os.MkdirAll(mtsDir, 0755); err != nil {
-		fmt.Printf("MTSディレクトリ作成エラー: %v\n", err)
-		os.Exit(1)
-	}
+// loadEntries 入力ファイルのエントリーを全て読み込む
+// 複数のエクスポートを読み込んだ場合の扱いはwalkEntriesと同じ。標準出力には何も出力しない
+func loadEntries(cfg config) (loadResult, error) {
+	var entries []entry.Entry
+	result, err := walkEntries(cfg, func(e entry.Entry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	result.entries = entries
+	return result, err
+}
 
-	if err := os.MkdirAll(mdsDir, 0755); err != nil {
-		fmt.Printf("MDSディレクトリ作成エラー: %v\n", err)
-		os.Exit(1)
+// walkEntries 入力ファイルのエントリーを順にfnに渡す（-skip-drafts の下書きは除く）
+// 入力が1つのエクスポートだけであれば解析しながら1件ずつ渡すため、メモリーの使用量はエクスポートの大きさによらない。
+// zipに含まれるファイルなども含めて複数のエクスポートがある場合は、全て読み込んで重複を除いてマージしてから渡す。
+// fnがエラーを返すとそこで読み込みを中止する。結果のentriesは空のまま返す
+func walkEntries(cfg config, fn func(entry.Entry) error) (loadResult, error) {
+	var result loadResult
+	emit := func(e entry.Entry) error {
+		if cfg.skipDrafts && e.Status == entry.StatusDraft {
+			result.drafts++
+			return nil
+		}
+		return fn(e)
 	}
 
-	// テスト用に最初の10件のみ処理
-	testEntries := entries
-	if len(entries) > 10 {
-		testEntries = entries[:10]
-		fmt.Printf("テストモード: 最初の10件のみ処理します\n")
+	var sources []merger.Source
+	for _, filename := range cfg.inputs {
+		err := input.Walk(filename, func(name string, r io.Reader, only bool) error {
+			parser := cfg.parse.newParser(name, r)
+			defer func() {
+				result.diagnostics = append(result.diagnostics, parser.Diagnostics()...)
+				result.encodings = append(result.encodings, sourceEncoding{name, parser.DetectedEncoding()})
+			}()
+
+			// 唯一のエクスポートはマージせずにそのまま渡す
+			streaming := only && len(cfg.inputs) == 1
+			src := merger.Source{Name: name}
+			for e, err := range parser.All() {
+				if err != nil {
+					return err
+				}
+				if streaming {
+					if err := emit(e); err != nil {
+						return err
+					}
+					continue
+				}
+				src.Entries = append(src.Entries, e)
+			}
+			if !streaming {
+				sources = append(sources, src)
+			}
+			return nil
+		})
+		if err != nil {
+			return result, fmt.Errorf("ファイル解析エラー: %w", err)
+		}
 	}
 
-	// 各エントリーを2つの形式で出力
-	for i, e := range testEntries {
-		filename := generator.GenerateFilename(e)
+	var entries []entry.Entry
+	if len(sources) > 1 {
+		merged, report := merger.Merge(sources, cfg.policy)
+		entries, result.merge = merged, &report
+	} else {
+		for _, src := range sources {
+			entries = append(entries, src.Entries...)
+		}
+	}
+	for _, e := range entries {
+		if err := emit(e); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
 
-		// MT形式でmtsフォルダに出力
-		mtFilename := strings.Replace(filename, ".md", ".txt", 1)
-		mtFilepath := filepath.Join(mtsDir, mtFilename)
-		mtContent := generator.GenerateMTContent(e)
+// printLoadWarnings 読み込み時に見つかった問題の件数を標準エラー出力に表示
+// （list・showの標準出力を他のコマンドに渡せるように分けておく）
+func printLoadWarnings(result loadResult) {
+	if n := len(result.diagnostics); n > 0 {
+		fmt.Fprintf(os.Stderr, "警告: 解析中に%d件の問題が見つかりました（mttohmd validate で詳細を確認できます）\n", n)
+	}
+	if result.merge != nil && len(result.merge.Duplicates) > 0 {
+		fmt.Fprintf(os.Stderr, "重複: %d件のエントリーを1つにまとめました\n", len(result.merge.Duplicates))
+	}
+}
 
-		if err := os.WriteFile(mtFilepath, []byte(mtContent), 0644); err != nil {
-			fmt.Printf("MTファイル書き込みエラー (%s): %v\n", mtFilename, err)
-		} else {
-			fmt.Printf("%d: MTS/%s を作成しました\n", i+1, mtFilename)
-		}
+// formatEntryDate 一覧表示用の日付（解析できなかった日付は元の表記のまま）
+func formatEntryDate(e entry.Entry) string {
+	if e.DateTime.IsZero() {
+		return e.Date
+	}
+	return e.DateTime.Format("2006-01-02 15:04")
+}
 
-		// Markdown形式でmdsフォルダに出力
-		mdFilepath := filepath.Join(mdsDir, filename)
-		mdContent := converter.ToMarkdown(e)
+// writeFile ファイルを書き込む（テンプレートで指定したサブディレクトリも作成）
+func writeFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
 
-		if err := os.WriteFile(mdFilepath, []byte(mdContent), 0644); err != nil {
-			fmt.Printf("Markdownファイル書き込みエラー (%s): %v\n", filename, err)
+// printMergeReport マージ結果を表示
+func printMergeReport(report merger.Report) {
+	fmt.Printf("マージ完了: %d個のエクスポートから%d個のエントリーを読み込み、%d個にまとめました\n",
+		report.Sources, report.Total, report.Merged)
+	if len(report.Duplicates) == 0 {
+		return
+	}
+	fmt.Printf("重複: %d件（うち内容の異なるもの %d件）\n", len(report.Duplicates), report.Conflicts())
+	for _, d := range report.Duplicates {
+		if d.Identical {
+			fmt.Printf("  %s: 同一の内容のため %s を採用\n", d.Title, d.Kept)
 		} else {
-			fmt.Printf("%d: MDS/%s を作成しました\n", i+1, filename)
+			fmt.Printf("  %s: %s を採用し %s を破棄\n", d.Title, d.Kept, d.Dropped)
 		}
 	}
+}
 
-	fmt.Println("変換完了！")
+// printCollisions ファイル名の衝突と割り当てた名前を一覧表示
+func printCollisions(collisions []generator.Collision) {
+	if len(collisions) == 0 {
+		return
+	}
+	fmt.Printf("警告: ファイル名の衝突が%d件ありました\n", len(collisions))
+	for _, c := range collisions {
+		fmt.Printf("  %s: %s と重複するため %s として出力しました\n", c.Name, c.Existing, c.Resolved)
+	}
+}
+
+// printDiagnostics 解析中に見つかった問題を一覧表示
+func printDiagnostics(diagnostics []entry.Diagnostic) {
+	if len(diagnostics) == 0 {
+		return
+	}
+	fmt.Printf("警告: 解析中に%d件の問題が見つかりました\n", len(diagnostics))
+	for _, d := range diagnostics {
+		fmt.Printf("  %s\n", d)
+	}
 }
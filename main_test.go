@@ -0,0 +1,113 @@
+package main
+
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mttohmd/entry"
+)
+
+// walkTestExport 3件目の日付が不正なエクスポート（最後まで解析すると診断が1件出る）
+const walkTestExport = `TITLE: A
+DATE: 01/01/2023 10:00:00
+-----
+BODY:
+a
+-----
+--------
+TITLE: B
+STATUS: Draft
+DATE: 01/02/2023 10:00:00
+-----
+BODY:
+b
+-----
+--------
+TITLE: C
+DATE: bogus
+-----
+BODY:
+c
+-----
+--------
+`
+
+func TestWalkEntriesStreamsSingleExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.txt")
+	if err := os.WriteFile(path, []byte(walkTestExport), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, cfg, err := parseArgs([]string{"convert", "-skip-drafts", path}, io.Discard)
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+
+	// 1件目を受け取った時点で中止すると、残りのエントリーは解析されない
+	stop := errors.New("stop")
+	var titles []string
+	result, err := walkEntries(cfg, func(e entry.Entry) error {
+		titles = append(titles, e.Title)
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("Expected stop error, got %v", err)
+	}
+	if len(titles) != 1 || titles[0] != "A" {
+		t.Errorf("Expected only A, got %v", titles)
+	}
+	if len(result.diagnostics) != 0 || result.merge != nil {
+		t.Errorf("Expected no diagnostics and no merge, got %v, %v", result.diagnostics, result.merge)
+	}
+
+	// 最後まで読むと下書きを除いて渡し、診断も集める
+	result, err = loadEntries(cfg)
+	if err != nil {
+		t.Fatalf("loadEntries failed: %v", err)
+	}
+	if len(result.entries) != 2 || result.entries[1].Title != "C" || result.drafts != 1 {
+		t.Errorf("Expected A and C with 1 draft skipped, got %d entries, %d drafts", len(result.entries), result.drafts)
+	}
+	if len(result.diagnostics) != 1 || len(result.encodings) != 1 {
+		t.Errorf("Expected 1 diagnostic and 1 encoding, got %v, %v", result.diagnostics, result.encodings)
+	}
+}
+
+func TestWalkEntriesMergesExportsInZip(t *testing.T) {
+	// 1つのzipに複数のエクスポートがあれば、全て読み込んでからマージして渡す
+	path := filepath.Join(t.TempDir(), "exports.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for _, name := range []string{"old.txt", "new.txt"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, walkTestExport)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	_, cfg, err := parseArgs([]string{"convert", path}, io.Discard)
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+	result, err := loadEntries(cfg)
+	if err != nil {
+		t.Fatalf("loadEntries failed: %v", err)
+	}
+	if result.merge == nil || len(result.merge.Duplicates) != 3 {
+		t.Fatalf("Expected 3 duplicates merged, got %+v", result.merge)
+	}
+	if len(result.entries) != 3 {
+		t.Errorf("Expected 3 entries, got %d", len(result.entries))
+	}
+}
@@ -0,0 +1,164 @@
+package merger
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"mttohmd/entry"
+)
+
+// Policy 重複したエントリーの内容が異なる場合にどちらを残すか
+type Policy int
+
+const (
+	// PolicyNewestExport 新しいエクスポートのエントリーを残す
+	// エクスポートの新しさは含まれるエントリーの最新のDATEで判定し、同じなら後に指定した方を新しいとみなす
+	PolicyNewestExport Policy = iota
+	// PolicyLongestBody 本文（追記を含む）が長い方のエントリーを残す
+	PolicyLongestBody
+)
+
+// ParsePolicy 名前からPolicyを返す
+func ParsePolicy(name string) (Policy, error) {
+	switch name {
+	case "newest":
+		return PolicyNewestExport, nil
+	case "longest":
+		return PolicyLongestBody, nil
+	}
+	return PolicyNewestExport, fmt.Errorf("未対応のマージ方針です: %s", name)
+}
+
+// String 方針の名前を返す
+func (p Policy) String() string {
+	switch p {
+	case PolicyNewestExport:
+		return "newest"
+	case PolicyLongestBody:
+		return "longest"
+	}
+	return fmt.Sprintf("Policy(%d)", int(p))
+}
+
+// Source 1つのエクスポートファイルから読み込んだエントリー一覧
+type Source struct {
+	Name    string
+	Entries []entry.Entry
+}
+
+// Duplicate 重複として検出されたエントリー
+type Duplicate struct {
+	Key       string
+	Title     string
+	Kept      string // 残したエントリーのエクスポート名
+	Dropped   string // 捨てたエントリーのエクスポート名
+	Identical bool   // 内容が完全に一致していたかどうか
+}
+
+// Report マージ結果の集計
+type Report struct {
+	Sources    int
+	Total      int
+	Merged     int
+	Duplicates []Duplicate
+}
+
+// Conflicts 内容が異なっていた重複の数を返す
+func (r Report) Conflicts() int {
+	n := 0
+	for _, d := range r.Duplicates {
+		if !d.Identical {
+			n++
+		}
+	}
+	return n
+}
+
+// Key 重複判定に使うキーを返す（Basenameがあればそれを、なければ日付とタイトルを使う）
+func Key(e entry.Entry) string {
+	if e.Basename != "" {
+		return "basename:" + e.Basename
+	}
+	date := e.Date
+	if !e.DateTime.IsZero() {
+		date = e.DateTime.UTC().Format("2006-01-02T15:04:05")
+	}
+	return "date:" + date + "\x00title:" + strings.TrimSpace(e.Title)
+}
+
+// Merge 複数のエクスポートのエントリーを重複を除いて1つにまとめる
+// 結果は最初に出現した順に並び、重複は方針に従ってどちらか一方に置き換える
+func Merge(sources []Source, policy Policy) ([]entry.Entry, Report) {
+	report := Report{Sources: len(sources)}
+
+	latest := make([]time.Time, len(sources))
+	for i, src := range sources {
+		latest[i] = latestDate(src)
+	}
+
+	var merged []entry.Entry
+	var origins []int // mergedの各エントリーを読み込んだsourcesの添字
+	index := map[string]int{}
+
+	for si, src := range sources {
+		for _, e := range src.Entries {
+			report.Total++
+			key := Key(e)
+
+			i, found := index[key]
+			if !found {
+				index[key] = len(merged)
+				merged = append(merged, e)
+				origins = append(origins, si)
+				continue
+			}
+
+			d := Duplicate{
+				Key:       key,
+				Title:     e.Title,
+				Identical: reflect.DeepEqual(merged[i], e),
+			}
+			o := origins[i]
+			newer := !latest[si].Before(latest[o])
+			if !d.Identical && prefer(e, merged[i], newer, policy) {
+				d.Kept, d.Dropped = src.Name, sources[o].Name
+				merged[i] = e
+				origins[i] = si
+			} else {
+				d.Kept, d.Dropped = sources[o].Name, src.Name
+			}
+			report.Duplicates = append(report.Duplicates, d)
+		}
+	}
+
+	report.Merged = len(merged)
+	return merged, report
+}
+
+// prefer 後から見つかったエントリーcandidateを既存のcurrentより優先するかどうか
+// newerはcandidateのエクスポートがcurrentのエクスポートと同じかより新しいかどうか
+func prefer(candidate, current entry.Entry, newer bool, policy Policy) bool {
+	switch policy {
+	case PolicyLongestBody:
+		return bodyLength(candidate) > bodyLength(current)
+	}
+	return newer
+}
+
+// latestDate エクスポートに含まれるエントリーの最新の日時（日付のあるエントリーがなければゼロ値）
+func latestDate(src Source) time.Time {
+	var latest time.Time
+	for _, e := range src.Entries {
+		if e.DateTime.After(latest) {
+			latest = e.DateTime
+		}
+	}
+	return latest
+}
+
+// bodyLength 本文と追記を合わせた長さ
+func bodyLength(e entry.Entry) int {
+	return len(e.Body) + len(e.ExtendedBody)
+}
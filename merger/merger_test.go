@@ -0,0 +1,152 @@
+package merger
+
+import (
+	"testing"
+	"time"
+
+	"mttohmd/entry"
+)
+
+func TestMerge(t *testing.T) {
+	date := time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	old := Source{
+		Name: "old.txt",
+		Entries: []entry.Entry{
+			{Title: "A", Basename: "2023/01/15/120000", Body: "長い本文です。長い本文です。"},
+			{Title: "B", Basename: "2023/01/16/120000", Body: "同じ本文"},
+			{Title: "C", DateTime: date, Body: "basenameなし"},
+		},
+	}
+	newer := Source{
+		Name: "new.txt",
+		Entries: []entry.Entry{
+			{Title: "A（改題）", Basename: "2023/01/15/120000", Body: "短い本文"},
+			{Title: "B", Basename: "2023/01/16/120000", Body: "同じ本文"},
+			{Title: "C", DateTime: date, Body: "basenameなしの更新"},
+			{Title: "D", Basename: "2023/01/17/120000", Body: "新しいエントリー"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		policy   Policy
+		expected []string
+	}{
+		{
+			name:     "新しいエクスポートを優先",
+			policy:   PolicyNewestExport,
+			expected: []string{"短い本文", "同じ本文", "basenameなしの更新", "新しいエントリー"},
+		},
+		{
+			name:     "長い本文を優先",
+			policy:   PolicyLongestBody,
+			expected: []string{"長い本文です。長い本文です。", "同じ本文", "basenameなしの更新", "新しいエントリー"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, report := Merge([]Source{old, newer}, tt.policy)
+
+			if len(merged) != len(tt.expected) {
+				t.Fatalf("Expected %d entries, got %d", len(tt.expected), len(merged))
+			}
+			for i, body := range tt.expected {
+				if merged[i].Body != body {
+					t.Errorf("merged[%d].Body = %q, want %q", i, merged[i].Body, body)
+				}
+			}
+
+			if report.Sources != 2 || report.Total != 7 || report.Merged != 4 {
+				t.Errorf("Unexpected report: %+v", report)
+			}
+			if len(report.Duplicates) != 3 {
+				t.Fatalf("Expected 3 duplicates, got %d", len(report.Duplicates))
+			}
+			if report.Conflicts() != 2 {
+				t.Errorf("Expected 2 conflicts, got %d", report.Conflicts())
+			}
+			if !report.Duplicates[1].Identical || report.Duplicates[1].Kept != "old.txt" {
+				t.Errorf("Identical duplicate should keep the first entry: %+v", report.Duplicates[1])
+			}
+		})
+	}
+}
+
+func TestMergeReportKeptAndDropped(t *testing.T) {
+	a := Source{Name: "a.txt", Entries: []entry.Entry{{Title: "X", Basename: "x", Body: "1"}}}
+	b := Source{Name: "b.txt", Entries: []entry.Entry{{Title: "X", Basename: "x", Body: "22"}}}
+
+	_, report := Merge([]Source{a, b}, PolicyNewestExport)
+	d := report.Duplicates[0]
+	if d.Kept != "b.txt" || d.Dropped != "a.txt" || d.Identical {
+		t.Errorf("Unexpected duplicate: %+v", d)
+	}
+
+	_, report = Merge([]Source{b, a}, PolicyLongestBody)
+	d = report.Duplicates[0]
+	if d.Kept != "b.txt" || d.Dropped != "a.txt" {
+		t.Errorf("Unexpected duplicate: %+v", d)
+	}
+}
+
+func TestMergeNewestByDate(t *testing.T) {
+	// 新しいエクスポートを先に指定しても、最新のDATEを含むエクスポートを優先する
+	newer := Source{Name: "new.txt", Entries: []entry.Entry{
+		{Title: "X", Basename: "x", Body: "更新後", DateTime: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Title: "Y", Basename: "y", DateTime: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}}
+	old := Source{Name: "old.txt", Entries: []entry.Entry{
+		{Title: "X", Basename: "x", Body: "更新前", DateTime: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}}
+
+	merged, report := Merge([]Source{newer, old}, PolicyNewestExport)
+	if merged[0].Body != "更新後" {
+		t.Errorf("merged[0].Body = %q, want %q", merged[0].Body, "更新後")
+	}
+	d := report.Duplicates[0]
+	if d.Kept != "new.txt" || d.Dropped != "old.txt" {
+		t.Errorf("Unexpected duplicate: %+v", d)
+	}
+
+	merged, _ = Merge([]Source{old, newer}, PolicyNewestExport)
+	if merged[0].Body != "更新後" {
+		t.Errorf("merged[0].Body = %q, want %q", merged[0].Body, "更新後")
+	}
+}
+
+func TestKey(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+
+	// Basenameがあればタイトルや日付が違っても同じエントリー
+	a := entry.Entry{Title: "旧タイトル", Basename: "2023/01/15/120000"}
+	b := entry.Entry{Title: "新タイトル", Basename: "2023/01/15/120000", Date: "01/15/2023 12:00:00"}
+	if Key(a) != Key(b) {
+		t.Errorf("Key() should match by Basename: %q != %q", Key(a), Key(b))
+	}
+
+	// Basenameがなければ日時とタイトルで判定（タイムゾーンの違いは無視）
+	c := entry.Entry{Title: "タイトル", DateTime: time.Date(2023, 1, 15, 21, 0, 0, 0, jst)}
+	d := entry.Entry{Title: "タイトル ", DateTime: time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC)}
+	if Key(c) != Key(d) {
+		t.Errorf("Key() should match by date and title: %q != %q", Key(c), Key(d))
+	}
+
+	e := entry.Entry{Title: "別のタイトル", DateTime: c.DateTime}
+	if Key(c) == Key(e) {
+		t.Error("Key() should differ for different titles")
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	for _, p := range []Policy{PolicyNewestExport, PolicyLongestBody} {
+		result, err := ParsePolicy(p.String())
+		if err != nil || result != p {
+			t.Errorf("ParsePolicy(%q) = %v, %v", p.String(), result, err)
+		}
+	}
+	if _, err := ParsePolicy("oldest"); err == nil {
+		t.Error("ParsePolicy(oldest) expected error, got nil")
+	}
+}
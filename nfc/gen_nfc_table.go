@@ -0,0 +1,227 @@
+//go:build ignore
+
+// gen_nfc_table.go Unicode文字データベース（UCD）からnfc_table.goを生成する
+//
+//	go run gen_nfc_table.go                         # unicode.orgから取得
+//	go run gen_nfc_table.go -version 15.1.0         # Unicodeのバージョンを指定
+//	go run gen_nfc_table.go -ucd ./ucd              # ダウンロード済みのUnicodeData.txtとCompositionExclusions.txtを使う
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ucdURL UCDの配布元（%sはUnicodeのバージョン）
+const ucdURL = "https://www.unicode.org/Public/%s/ucd/"
+
+// ucd 生成に使うUCDのデータ
+type ucd struct {
+	decompositions map[rune][]rune // 正準分解（互換分解は含まない）
+	classes        map[rune]uint8  // 0以外の正準結合クラス
+	exclusions     map[rune]bool   // CompositionExclusions.txtの合成除外文字
+}
+
+func main() {
+	version := flag.String("version", "14.0.0", "Unicodeのバージョン")
+	dir := flag.String("ucd", "", "UnicodeData.txtとCompositionExclusions.txtのあるディレクトリ（省略時はunicode.orgから取得）")
+	output := flag.String("output", "nfc_table.go", "出力するファイル")
+	flag.Parse()
+
+	var data ucd
+	if err := load(*dir, *version, "UnicodeData.txt", data.readUnicodeData); err != nil {
+		log.Fatal(err)
+	}
+	if err := load(*dir, *version, "CompositionExclusions.txt", data.readExclusions); err != nil {
+		log.Fatal(err)
+	}
+
+	code, err := format.Source(data.generate(*version))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, code, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// load UCDのファイルを開いてreadに渡す（dirが空ならunicode.orgから取得）
+func load(dir, version, name string, read func(io.Reader) error) error {
+	if dir != "" {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return read(f)
+	}
+
+	url := fmt.Sprintf(ucdURL, version) + name
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	return read(resp.Body)
+}
+
+// readUnicodeData UnicodeData.txtから正準結合クラス（4列目）と正準分解（6列目）を読み取る
+func (d *ucd) readUnicodeData(r io.Reader) error {
+	d.decompositions = map[rune][]rune{}
+	d.classes = map[rune]uint8{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ";")
+		if len(fields) < 6 {
+			continue
+		}
+		c, err := parseRune(fields[0])
+		if err != nil {
+			return err
+		}
+
+		class, err := strconv.ParseUint(fields[3], 10, 8)
+		if err != nil {
+			return fmt.Errorf("%q: %w", scanner.Text(), err)
+		}
+		if class != 0 {
+			d.classes[c] = uint8(class)
+		}
+
+		// 「<compat>」などの付いた互換分解はNFCでは使わない
+		if fields[5] == "" || strings.HasPrefix(fields[5], "<") {
+			continue
+		}
+		var decomposition []rune
+		for _, s := range strings.Fields(fields[5]) {
+			r, err := parseRune(s)
+			if err != nil {
+				return err
+			}
+			decomposition = append(decomposition, r)
+		}
+		if len(decomposition) > 2 {
+			return fmt.Errorf("%U: 3文字以上への正準分解には対応していません", c)
+		}
+		d.decompositions[c] = decomposition
+	}
+	return scanner.Err()
+}
+
+// readExclusions CompositionExclusions.txtから合成除外文字を読み取る
+func (d *ucd) readExclusions(r io.Reader) error {
+	d.exclusions = map[rune]bool{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		// 「0958」のような1文字か「0958..095F」のような範囲
+		first, last, isRange := strings.Cut(line, "..")
+		lo, err := parseRune(first)
+		if err != nil {
+			return err
+		}
+		hi := lo
+		if isRange {
+			if hi, err = parseRune(last); err != nil {
+				return err
+			}
+		}
+		for c := lo; c <= hi; c++ {
+			d.exclusions[c] = true
+		}
+	}
+	return scanner.Err()
+}
+
+// composable NFCで合成される文字かどうか（Full_Composition_Exclusionに当たらない）
+// 合成除外文字・1文字への分解・結合文字を含む分解は合成しない
+func (d *ucd) composable(c rune) bool {
+	decomposition := d.decompositions[c]
+	return len(decomposition) == 2 && !d.exclusions[c] && d.classes[c] == 0 && d.classes[decomposition[0]] == 0
+}
+
+// generate 3つの表をGoのソースコードとして出力
+func (d *ucd) generate(version string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by gen_nfc_table.go from the Unicode Character Database (Unicode %s). DO NOT EDIT.\n\n", version)
+	b.WriteString("package nfc\n\n")
+
+	b.WriteString("// canonicalDecompositions 文字の正準分解（1段階のみ、2文字目が0なら1文字への分解）\n")
+	b.WriteString("var canonicalDecompositions = map[rune][2]rune{\n")
+	var items []string
+	for _, c := range slices.Sorted(maps.Keys(d.decompositions)) {
+		pair := [2]rune{d.decompositions[c][0]}
+		if len(d.decompositions[c]) == 2 {
+			pair[1] = d.decompositions[c][1]
+		}
+		items = append(items, fmt.Sprintf("0x%04X: {0x%04X, 0x%04X},", c, pair[0], pair[1]))
+	}
+	writeItems(&b, items, 3)
+	b.WriteString("}\n\n")
+
+	b.WriteString("// combiningClasses 0以外の正準結合クラス\n")
+	b.WriteString("var combiningClasses = map[rune]uint8{\n")
+	items = nil
+	for _, c := range slices.Sorted(maps.Keys(d.classes)) {
+		items = append(items, fmt.Sprintf("0x%04X: %d,", c, d.classes[c]))
+	}
+	writeItems(&b, items, 6)
+	b.WriteString("}\n\n")
+
+	b.WriteString("// canonicalCompositions NFCで合成される2文字の組と合成後の文字（合成除外文字を除く）\n")
+	b.WriteString("var canonicalCompositions = map[[2]rune]rune{\n")
+	var composed []rune
+	for c := range d.decompositions {
+		if d.composable(c) {
+			composed = append(composed, c)
+		}
+	}
+	slices.SortFunc(composed, func(x, y rune) int {
+		dx, dy := d.decompositions[x], d.decompositions[y]
+		return cmp.Or(cmp.Compare(dx[0], dy[0]), cmp.Compare(dx[1], dy[1]))
+	})
+	items = nil
+	for _, c := range composed {
+		items = append(items, fmt.Sprintf("{0x%04X, 0x%04X}: 0x%04X,", d.decompositions[c][0], d.decompositions[c][1], c))
+	}
+	writeItems(&b, items, 3)
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+// writeItems 要素を1行にperLine個ずつ出力
+func writeItems(b *bytes.Buffer, items []string, perLine int) {
+	for i := 0; i < len(items); i += perLine {
+		b.WriteString("\t" + strings.Join(items[i:min(i+perLine, len(items))], " ") + "\n")
+	}
+}
+
+// parseRune 「00C0」形式の16進数の符号位置を解釈
+func parseRune(s string) (rune, error) {
+	n, err := strconv.ParseUint(strings.TrimSpace(s), 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("符号位置を解釈できません: %q", s)
+	}
+	return rune(n), nil
+}
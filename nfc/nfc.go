@@ -0,0 +1,121 @@
+package nfc
+
+//go:generate go run gen_nfc_table.go -version 14.0.0
+
+// ハングル音節の合成・分解に使う定数（Unicode標準 3.12節）
+const (
+	hangulSBase  = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulLCount = 19
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulSCount = hangulLCount * hangulNCount
+)
+
+// Normalize 文字列をUnicode正規化形式C（NFC）に変換
+// macOSで作られたファイル名などの分解された濁点（か＋゛）を合成済みの文字（が）にそろえる
+func Normalize(s string) string {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return s
+	}
+
+	// 正準分解
+	var runes []rune
+	for _, r := range s {
+		runes = decompose(runes, r)
+	}
+
+	// 結合文字を結合クラス順に並べ替える（安定ソート）
+	for i := range runes {
+		for j := i; j > 0; j-- {
+			cc, prev := combiningClasses[runes[j]], combiningClasses[runes[j-1]]
+			if cc == 0 || prev <= cc {
+				break
+			}
+			runes[j], runes[j-1] = runes[j-1], runes[j]
+		}
+	}
+
+	return string(compose(runes))
+}
+
+// decompose 文字を再帰的に正準分解してrunesに追加
+func decompose(runes []rune, r rune) []rune {
+	if s := r - hangulSBase; 0 <= s && s < hangulSCount {
+		runes = append(runes, hangulLBase+s/hangulNCount, hangulVBase+(s%hangulNCount)/hangulTCount)
+		if t := s % hangulTCount; t != 0 {
+			runes = append(runes, hangulTBase+t)
+		}
+		return runes
+	}
+
+	d, ok := canonicalDecompositions[r]
+	if !ok {
+		return append(runes, r)
+	}
+	runes = decompose(runes, d[0])
+	if d[1] != 0 {
+		runes = decompose(runes, d[1])
+	}
+	return runes
+}
+
+// compose 正準分解・並べ替え済みの文字列を正準合成する
+func compose(runes []rune) []rune {
+	if len(runes) == 0 {
+		return runes
+	}
+
+	starter := 0
+	lastClass := int(combiningClasses[runes[0]])
+	if lastClass != 0 {
+		// 先頭が結合文字の場合は合成の対象にしない
+		lastClass = 256
+	}
+	n := 1
+	for _, r := range runes[1:] {
+		class := int(combiningClasses[r])
+		if c, ok := composePair(runes[starter], r); ok && (lastClass < class || lastClass == 0) {
+			runes[starter] = c
+			continue
+		}
+		if class == 0 {
+			starter = n
+		}
+		lastClass = class
+		runes[n] = r
+		n++
+	}
+	return runes[:n]
+}
+
+// composePair 2文字を合成した文字を返す
+func composePair(a, b rune) (rune, bool) {
+	if l := a - hangulLBase; 0 <= l && l < hangulLCount {
+		if v := b - hangulVBase; 0 <= v && v < hangulVCount {
+			return hangulSBase + (l*hangulVCount+v)*hangulTCount, true
+		}
+	}
+	if s := a - hangulSBase; 0 <= s && s < hangulSCount && s%hangulTCount == 0 {
+		if t := b - hangulTBase; 0 < t && t < hangulTCount {
+			return a + t, true
+		}
+	}
+	c, ok := canonicalCompositions[[2]rune{a, b}]
+	return c, ok
+}
+
+// CombiningClass 文字の正準結合クラス（結合文字以外は0）
+func CombiningClass(r rune) uint8 {
+	return combiningClasses[r]
+}
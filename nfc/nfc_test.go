@@ -0,0 +1,32 @@
+package nfc
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "ASCII", input: "Hello, World", expected: "Hello, World"},
+		{name: "分解された濁点", input: "\u304B\u3099", expected: "\u304C"},
+		{name: "合成済みの文字がない組み合わせ", input: "\u304D\u309A", expected: "\u304D\u309A"},
+		{name: "ラテン文字", input: "e\u0301", expected: "\u00E9"},
+		{name: "1文字への正準分解", input: "\u212B", expected: "\u00C5"},
+		{name: "結合文字の並べ替え", input: "a\u0302\u0323", expected: "\u1EAD"},
+		{name: "ハングル", input: "\u1100\u1161\u11A8", expected: "\uAC01"},
+		{name: "合成除外文字", input: "\u0958", expected: "\u0915\u093C"},
+		{name: "結合文字のみの分解", input: "\u0344", expected: "\u0308\u0301"},
+		{name: "先頭の結合文字", input: "\u0301a", expected: "\u0301a"},
+		{name: "合成しない結合文字の並べ替え", input: "q\u0307\u0323", expected: "q\u0323\u0307"},
+		{name: "半角カナはそのまま", input: "\uFF76\uFF9E", expected: "\uFF76\uFF9E"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := Normalize(tt.input); result != tt.expected {
+				t.Errorf("Normalize(%+q) = %+q, want %+q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"mttohmd/converter"
+	"mttohmd/entry"
+	"mttohmd/generator"
+)
+
+// defaultReverseOutput reverseの既定の出力ファイル
+const defaultReverseOutput = "mds.export.txt"
+
+// runReverse Markdownファイル（ディレクトリ指定時は配下の*.md）を読み込み、
+// 日付順に並べて1つのMT形式のエクスポートとして出力
+func runReverse(paths []string, output string) error {
+	if output == "" {
+		output = defaultReverseOutput
+	}
+
+	files, err := listFiles(paths, ".md")
+	if err != nil {
+		return err
+	}
+
+	var entries []entry.Entry
+	failed := 0
+	for _, filename := range files {
+		content, err := os.ReadFile(filename)
+		if err == nil {
+			var e entry.Entry
+			if e, err = converter.FromMarkdown(string(content)); err == nil {
+				entries = append(entries, e)
+				continue
+			}
+		}
+		fmt.Printf("警告: %s: %v\n", filename, err)
+		failed++
+	}
+
+	sortByDate(entries)
+
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := generator.NewWriter(file)
+	for _, e := range entries {
+		if err := w.Write(e); err != nil {
+			return err
+		}
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		fmt.Printf("警告: %d個のファイルを読み込めませんでした\n", failed)
+	}
+	fmt.Printf("変換完了！ %d個のエントリーを %s に出力しました\n", len(entries), output)
+	return nil
+}
+
+// listFiles 指定されたファイルと、ディレクトリ配下の拡張子extのファイルを名前順に列挙
+func listFiles(paths []string, ext string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s が見つかりません", path)
+		} else if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if name != path && strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(name), ext) {
+				files = append(files, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// sortByDate エントリーを日時の古い順に並べ替え（日時のないエントリーは最後に元の順序で並べる）
+func sortByDate(entries []entry.Entry) {
+	slices.SortStableFunc(entries, func(a, b entry.Entry) int {
+		switch {
+		case a.DateTime.IsZero() || b.DateTime.IsZero():
+			if a.DateTime.IsZero() == b.DateTime.IsZero() {
+				return 0
+			}
+			if a.DateTime.IsZero() {
+				return 1
+			}
+			return -1
+		}
+		return a.DateTime.Compare(b.DateTime)
+	})
+}
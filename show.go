@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"mttohmd/converter"
+	"mttohmd/entry"
+)
+
+// runShow 1件のエントリーをMarkdownに変換して標準出力に表示
+func runShow(cfg config) error {
+	result, err := loadEntries(cfg)
+	if err != nil {
+		return err
+	}
+
+	e, err := selectEntry(result.entries, cfg.index, cfg.basename)
+	if err != nil {
+		return err
+	}
+
+	md := converter.ToMarkdownWithOptions(e, cfg.mdOptions)
+	if !strings.HasSuffix(md, "\n") {
+		md += "\n"
+	}
+	fmt.Print(md)
+
+	printLoadWarnings(result)
+	return nil
+}
+
+// selectEntry 番号（1から始まる、listの表示と同じ）またはBASENAMEでエントリーを選ぶ
+func selectEntry(entries []entry.Entry, index int, basename string) (entry.Entry, error) {
+	if basename != "" {
+		for _, e := range entries {
+			if e.Basename == basename {
+				return e, nil
+			}
+		}
+		return entry.Entry{}, fmt.Errorf("BASENAMEが %s のエントリーが見つかりません", basename)
+	}
+
+	if index < 1 || index > len(entries) {
+		return entry.Entry{}, fmt.Errorf("%d番目のエントリーはありません（全%d件）", index, len(entries))
+	}
+	return entries[index-1], nil
+}
@@ -0,0 +1,199 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"mttohmd/entry"
+	"mttohmd/nfc"
+)
+
+// maxSlugLength スラッグの最大文字数（単語の途中では切らない）
+const maxSlugLength = 80
+
+// kanaRomaji ひらがな1文字のヘボン式ローマ字
+var kanaRomaji = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ゔ': "vu",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+	'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo", 'ゎ': "wa", 'ゕ': "ka", 'ゖ': "ke",
+}
+
+// kanaDigraphs 拗音や外来語の表記など、2文字で1音になるひらがなのローマ字
+var kanaDigraphs = map[string]string{
+	"しぇ": "she", "じぇ": "je", "ちぇ": "che", "いぇ": "ye",
+	"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo", "ふゅ": "fyu",
+	"てぃ": "ti", "でぃ": "di", "とぅ": "tu", "どぅ": "du", "てゅ": "tyu", "でゅ": "dyu",
+	"うぃ": "wi", "うぇ": "we", "うぉ": "wo",
+	"ゔぁ": "va", "ゔぃ": "vi", "ゔぇ": "ve", "ゔぉ": "vo",
+	"つぁ": "tsa", "つぃ": "tsi", "つぇ": "tse", "つぉ": "tso",
+}
+
+func init() {
+	// き＋ゃ→kya、し＋ゃ→shaなどの拗音
+	consonants := map[rune]string{
+		'き': "ky", 'ぎ': "gy", 'に': "ny", 'ひ': "hy", 'び': "by", 'ぴ': "py", 'み': "my", 'り': "ry",
+		'し': "sh", 'じ': "j", 'ち': "ch", 'ぢ': "j",
+	}
+	vowels := map[rune]string{'ゃ': "a", 'ゅ': "u", 'ょ': "o"}
+	for c, cr := range consonants {
+		for v, vr := range vowels {
+			kanaDigraphs[string([]rune{c, v})] = cr + vr
+		}
+	}
+}
+
+// slugClass スラッグを作るときの文字の種類（種類が変わるところで単語を区切る）
+type slugClass int
+
+const (
+	slugSeparator slugClass = iota
+	slugASCII
+	slugHiragana
+	slugKatakana
+	slugOther
+)
+
+// Generate タイトルからURLやファイル名に使える小文字のローマ字スラッグを生成
+// ひらがな・カタカナはヘボン式ローマ字に、英数字はそのまま小文字にして「-」でつなぐ
+// 漢字などローマ字にできない文字が多いタイトルは、BASENAMEや日時から生成する
+func Generate(e entry.Entry) string {
+	slug, converted, unconverted := romanize(e.Title)
+	if slug != "" && converted >= unconverted {
+		return slug
+	}
+
+	// はてなブログのBasename（2023/01/15/123456）は日付ごと使う
+	if e.Basename != "" {
+		if slug, _, _ := romanize(strings.ReplaceAll(e.Basename, "/", "-")); slug != "" {
+			return slug
+		}
+	}
+	if !e.DateTime.IsZero() {
+		return e.DateTime.Format("2006-01-02-150405")
+	}
+	return slug
+}
+
+// romanize 文字列をローマ字のスラッグに変換し、変換できた文字数とできなかった文字数を返す
+func romanize(s string) (slug string, converted, unconverted int) {
+	var words []string
+	var word strings.Builder
+	var kana []rune
+	class := slugSeparator
+
+	flush := func() {
+		if len(kana) > 0 {
+			word.WriteString(kanaToRomaji(kana))
+			kana = kana[:0]
+		}
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range nfc.Normalize(s) {
+		// 全角英数字は半角として扱う
+		if 0xFF01 <= r && r <= 0xFF5E {
+			r -= 0xFEE0
+		}
+
+		if r == 'ー' || r == '\'' {
+			// 長音記号とアポストロフィーは単語を区切らず読み飛ばす
+			continue
+		}
+		c := classify(r)
+		if c != class {
+			flush()
+			class = c
+		}
+
+		switch c {
+		case slugASCII:
+			word.WriteRune(unicode.ToLower(r))
+			converted++
+		case slugHiragana:
+			kana = append(kana, r)
+			converted++
+		case slugKatakana:
+			kana = append(kana, r-0x60)
+			converted++
+		case slugOther:
+			unconverted++
+		}
+	}
+	flush()
+
+	slug = strings.Join(words, "-")
+	for len(words) > 1 && len(slug) > maxSlugLength {
+		words = words[:len(words)-1]
+		slug = strings.Join(words, "-")
+	}
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+	}
+	return slug, converted, unconverted
+}
+
+// classify スラッグ用に文字の種類を判定
+func classify(r rune) slugClass {
+	switch {
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		return slugASCII
+	case 'ぁ' <= r && r <= 'ゖ':
+		return slugHiragana
+	case 'ァ' <= r && r <= 'ヶ':
+		return slugKatakana
+	case unicode.IsLetter(r) || unicode.IsDigit(r):
+		return slugOther
+	}
+	return slugSeparator
+}
+
+// kanaToRomaji ひらがなの並びをヘボン式ローマ字に変換（促音は次の子音を重ねる）
+func kanaToRomaji(kana []rune) string {
+	var b strings.Builder
+	sokuon := false
+	for i := 0; i < len(kana); i++ {
+		var romaji string
+		if i+1 < len(kana) {
+			if d, ok := kanaDigraphs[string(kana[i:i+2])]; ok {
+				romaji = d
+				i++
+			}
+		}
+		if romaji == "" {
+			if kana[i] == 'っ' {
+				sokuon = true
+				continue
+			}
+			romaji = kanaRomaji[kana[i]]
+		}
+
+		if sokuon && romaji != "" && !strings.ContainsRune("aiueon", rune(romaji[0])) {
+			if strings.HasPrefix(romaji, "ch") {
+				b.WriteByte('t')
+			} else {
+				b.WriteByte(romaji[0])
+			}
+		}
+		sokuon = false
+		b.WriteString(romaji)
+	}
+	return b.String()
+}
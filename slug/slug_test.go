@@ -0,0 +1,59 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"mttohmd/entry"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    entry.Entry
+		expected string
+	}{
+		{name: "英語のタイトル", entry: entry.Entry{Title: "Hello, World! Don't Panic"}, expected: "hello-world-dont-panic"},
+		{name: "ひらがな", entry: entry.Entry{Title: "さくら"}, expected: "sakura"},
+		{name: "カタカナと長音", entry: entry.Entry{Title: "コーヒー"}, expected: "kohi"},
+		{name: "ヘボン式の綴り", entry: entry.Entry{Title: "しちふつじ"}, expected: "shichifutsuji"},
+		{name: "拗音", entry: entry.Entry{Title: "きょうしゅうちゃ"}, expected: "kyoushuucha"},
+		{name: "促音", entry: entry.Entry{Title: "がっこう まっちゃ"}, expected: "gakkou-matcha"},
+		{name: "撥音", entry: entry.Entry{Title: "しんぶん"}, expected: "shinbun"},
+		{name: "外来語の表記", entry: entry.Entry{Title: "パーティー フォント ヴァイオリン"}, expected: "pati-fonto-vaiorin"},
+		{name: "文字の種類で単語を区切る", entry: entry.Entry{Title: "Goのテスト入門"}, expected: "go-no-tesuto"},
+		{name: "全角英数字", entry: entry.Entry{Title: "ＧＯ１２３"}, expected: "go123"},
+		{name: "分解された濁点", entry: entry.Entry{Title: "\u304B\u3099き"}, expected: "gaki"},
+		{
+			name:     "漢字のみのタイトルはBasename",
+			entry:    entry.Entry{Title: "日本語入門", Basename: "2023/01/15/123456"},
+			expected: "2023-01-15-123456",
+		},
+		{
+			name:     "漢字の多いタイトルは日時",
+			entry:    entry.Entry{Title: "東京の天気", DateTime: time.Date(2023, 1, 15, 14, 30, 45, 0, time.UTC)},
+			expected: "2023-01-15-143045",
+		},
+		{
+			name:     "漢字のみで代わりの情報もない",
+			entry:    entry.Entry{Title: "日本語入門"},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := Generate(tt.entry); result != tt.expected {
+				t.Errorf("Generate() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateLength(t *testing.T) {
+	result := Generate(entry.Entry{Title: strings.Repeat("word ", 30)})
+	if len(result) > maxSlugLength || strings.HasSuffix(result, "-") || !strings.HasSuffix(result, "word") {
+		t.Errorf("Expected slug cut at a word boundary within %d bytes, got %q", maxSlugLength, result)
+	}
+}
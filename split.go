@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"mttohmd/generator"
+	"mttohmd/input"
+)
+
+// splitの既定の出力ディレクトリ
+const defaultSplitOutput = "split"
+
+// maxSplitSize -max-size に指定できる最大のバイト数
+const maxSplitSize = 1 << 30
+
+// runSplit MT形式のエクスポートを読み込み、件数・バイト数の上限ごとのエクスポートに分けて出力
+// 入力はconvertと同じくgzip・zip・標準入力にも対応し、複数のエクスポートは重複を除いてマージする。
+// 出力ファイル名は最初の入力ファイル名に連番を付けたもの（例: blog.export.001.txt）
+func runSplit(cfg config) error {
+	if cfg.maxEntries <= 0 && cfg.maxBytes <= 0 {
+		return errors.New("-max-entries か -max-size を指定してください")
+	}
+	output := cfg.output
+	if output == "" {
+		output = defaultSplitOutput
+	}
+
+	result, err := loadEntries(cfg)
+	if err != nil {
+		return err
+	}
+	if result.merge != nil {
+		printMergeReport(*result.merge)
+	}
+
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return err
+	}
+
+	base := splitBaseName(cfg.inputs[0])
+	chunks := generator.SplitExport(result.entries, cfg.maxEntries, cfg.maxBytes)
+	for i, chunk := range chunks {
+		filename := filepath.Join(output, fmt.Sprintf("%s.%03d.txt", base, i+1))
+		if err := os.WriteFile(filename, []byte(chunk), 0644); err != nil {
+			return err
+		}
+		fmt.Printf("%d: %s を作成しました (%d bytes)\n", i+1, filename, len(chunk))
+		if cfg.maxBytes > 0 && len(chunk) > cfg.maxBytes {
+			fmt.Printf("警告: %s は1件のエントリーだけで -max-size を超えています\n", filename)
+		}
+	}
+
+	printDiagnostics(result.diagnostics)
+	if result.drafts > 0 {
+		fmt.Printf("下書き %d件を除外しました\n", result.drafts)
+	}
+	fmt.Printf("変換完了！ %d個のエントリーを%d個のファイルに分割しました\n", len(result.entries), len(chunks))
+	return nil
+}
+
+// splitBaseName 分割したファイル名の元になる名前（圧縮形式と最後の拡張子を除いたファイル名、標準入力は「stdin」）
+func splitBaseName(path string) string {
+	if path == input.Stdin {
+		return "stdin"
+	}
+	name := filepath.Base(path)
+	for _, ext := range []string{".gz", ".zip"} {
+		if strings.EqualFold(filepath.Ext(name), ext) {
+			name = name[:len(name)-len(ext)]
+		}
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// parseSize 「512KB」「5MB」のような単位付きのバイト数を解釈（maxSplitSizeまで）
+func parseSize(s string) (int, error) {
+	units := []struct {
+		suffix string
+		size   int
+	}{
+		{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+	}
+
+	number, unit := strings.ToUpper(strings.TrimSpace(s)), 1
+	for _, u := range units {
+		if n, ok := strings.CutSuffix(number, u.suffix); ok {
+			number, unit = strings.TrimSpace(n), u.size
+			break
+		}
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("サイズを解釈できません: %s", s)
+	}
+	// 掛け算の前に上限と比べて桁あふれを防ぐ
+	if n > maxSplitSize/unit {
+		return 0, fmt.Errorf("サイズが大きすぎます（最大1GB）: %s", s)
+	}
+	return n * unit, nil
+}
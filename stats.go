@@ -0,0 +1,126 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	"mttohmd/entry"
+)
+
+// statusOrder 集計結果に表示する公開状態の順序（それ以外の状態はこの後に名前順で表示）
+var statusOrder = []entry.Status{entry.StatusPublish, entry.StatusDraft, entry.StatusFuture}
+
+// runStats エクスポートのエントリー数・期間・本文の文字数・コメント数・カテゴリーなどを集計して表示
+func runStats(cfg config) error {
+	result, err := loadEntries(cfg)
+	if err != nil {
+		return err
+	}
+	entries := result.entries
+
+	statuses := map[entry.Status]int{}
+	years := map[int]int{}
+	categories := map[string]int{}
+	var first, last entry.Entry
+	undated, chars, comments, pings := 0, 0, 0, 0
+	for _, e := range entries {
+		statuses[e.Status]++
+		for _, c := range e.Categories {
+			categories[c.Name]++
+		}
+		chars += utf8.RuneCountInString(e.Body) + utf8.RuneCountInString(e.ExtendedBody)
+		comments += len(e.Comments)
+		pings += len(e.Pings)
+
+		if e.DateTime.IsZero() {
+			undated++
+			continue
+		}
+		years[e.DateTime.Year()]++
+		if first.DateTime.IsZero() || e.DateTime.Before(first.DateTime) {
+			first = e
+		}
+		if last.DateTime.IsZero() || e.DateTime.After(last.DateTime) {
+			last = e
+		}
+	}
+
+	fmt.Printf("エントリー: %d件\n", len(entries))
+	for _, status := range sortedStatuses(statuses) {
+		name := string(status)
+		if name == "" {
+			name = "(STATUSなし)"
+		}
+		fmt.Printf("  %s: %d件\n", name, statuses[status])
+	}
+	if result.drafts > 0 {
+		fmt.Printf("  (-skip-drafts で下書き %d件を除外)\n", result.drafts)
+	}
+	if !first.DateTime.IsZero() {
+		fmt.Printf("期間: %s 〜 %s\n", formatEntryDate(first), formatEntryDate(last))
+	}
+	if undated > 0 {
+		fmt.Printf("日付のないエントリー: %d件\n", undated)
+	}
+	if len(entries) > 0 {
+		fmt.Printf("本文の文字数: 合計 %d文字（1件あたり平均 %d文字）\n", chars, chars/len(entries))
+	}
+	fmt.Printf("コメント: %d件\n", comments)
+	fmt.Printf("トラックバック: %d件\n", pings)
+
+	if len(years) > 0 {
+		fmt.Println("年ごとのエントリー数:")
+		var keys []int
+		for year := range years {
+			keys = append(keys, year)
+		}
+		slices.Sort(keys)
+		for _, year := range keys {
+			fmt.Printf("  %d: %d件\n", year, years[year])
+		}
+	}
+
+	if len(categories) > 0 {
+		fmt.Printf("カテゴリー: %d種類\n", len(categories))
+		names := make([]string, 0, len(categories))
+		for name := range categories {
+			names = append(names, name)
+		}
+		// 件数の多い順、同じ件数なら名前順
+		slices.SortFunc(names, func(a, b string) int {
+			return cmp.Or(cmp.Compare(categories[b], categories[a]), cmp.Compare(a, b))
+		})
+		for _, name := range names {
+			fmt.Printf("  %s: %d件\n", name, categories[name])
+		}
+	}
+
+	if result.merge != nil {
+		fmt.Printf("マージ: %d個のエクスポートの%d件から重複 %d件を除外\n",
+			result.merge.Sources, result.merge.Total, len(result.merge.Duplicates))
+	}
+	if len(result.diagnostics) > 0 {
+		fmt.Printf("解析中の問題: %d件（mttohmd validate で詳細を確認できます）\n", len(result.diagnostics))
+	}
+	return nil
+}
+
+// sortedStatuses 集計に現れた公開状態をstatusOrderの順に並べる
+func sortedStatuses(counts map[entry.Status]int) []entry.Status {
+	var statuses []entry.Status
+	for _, status := range statusOrder {
+		if counts[status] > 0 {
+			statuses = append(statuses, status)
+		}
+	}
+	var others []entry.Status
+	for status := range counts {
+		if !status.Valid() {
+			others = append(others, status)
+		}
+	}
+	slices.Sort(others)
+	return append(statuses, others...)
+}
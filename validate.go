@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"mttohmd/converter"
+	"mttohmd/entry"
+	"mttohmd/generator"
+)
+
+// runValidate ファイルを書き出さずに、解析・ファイル名の生成・Markdownへの変換で問題がないか確認
+// 問題が見つかった場合は一覧を表示してエラーを返す
+func runValidate(cfg config) error {
+	result, err := loadEntries(cfg)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	for _, d := range result.diagnostics {
+		problems = append(problems, d.String())
+	}
+
+	// convertと同じ規則でファイル名を割り当て、衝突を確認
+	names := generator.NewNameRegistry()
+	for i, e := range result.entries {
+		label := fmt.Sprintf("エントリー%d (%s)", i+1, e.Title)
+
+		filename := generator.GenerateFilenameWithProfile(e, cfg.profile)
+		if cfg.filenameTemplate != nil {
+			name, err := cfg.filenameTemplate.Execute(e, i+1)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: ファイル名を生成できません: %v", label, err))
+				continue
+			}
+			filename = name
+		}
+		names.Reserve(cfg.layout.Path(e, filename, cfg.profile))
+
+		for _, p := range checkMarkdown(e) {
+			problems = append(problems, label+": "+p)
+		}
+	}
+	for _, c := range names.Collisions() {
+		problems = append(problems, fmt.Sprintf("ファイル名の衝突: %s: %s と重複するため %s として出力されます", c.Name, c.Existing, c.Resolved))
+	}
+
+	if len(problems) > 0 {
+		fmt.Printf("%d個のエントリーを確認し、%d件の問題が見つかりました\n", len(result.entries), len(problems))
+		for _, p := range problems {
+			fmt.Printf("  %s\n", p)
+		}
+		return fmt.Errorf("%d件の問題が見つかりました", len(problems))
+	}
+	fmt.Printf("%d個のエントリーを確認しました。問題は見つかりませんでした\n", len(result.entries))
+	return nil
+}
+
+// checkMarkdown Markdownに変換したフロントマターを読み戻し、タイトル・日付・カテゴリーが保たれているか確認
+// （本文はHTMLに戻さず、フロントマターだけを読む）
+func checkMarkdown(e entry.Entry) []string {
+	back, err := converter.ParseFrontMatter(converter.ToMarkdown(e))
+	if err != nil {
+		return []string{fmt.Sprintf("変換したMarkdownを読み込めません: %v", err)}
+	}
+
+	var problems []string
+	if back.Title != e.Title {
+		problems = append(problems, fmt.Sprintf("Markdownでタイトルが変わります: %q → %q", e.Title, back.Title))
+	}
+	if !e.DateTime.IsZero() && !back.DateTime.Equal(e.DateTime) {
+		problems = append(problems, fmt.Sprintf("Markdownで日付が変わります: %s → %s", e.Date, back.Date))
+	}
+	if before, after := e.CategoryNames(), back.CategoryNames(); !slices.Equal(before, after) {
+		problems = append(problems, fmt.Sprintf("Markdownでカテゴリーが変わります: [%s] → [%s]", strings.Join(before, ", "), strings.Join(after, ", ")))
+	}
+	return problems
+}